@@ -20,7 +20,20 @@ const (
 	height  = 28
 )
 
-var (
+// trainConfig is train subcommand configuration
+type trainConfig struct {
+	// path to data pattern directory
+	datadir string
+	// training defines type of learning
+	training string
+	// path to the saved model
+	model string
+}
+
+// restoreConfig is restore subcommand configuration
+type restoreConfig struct {
+	// path to the saved model
+	model string
 	// path to data pattern directory
 	datadir string
 	// path to input data
@@ -29,38 +42,95 @@ var (
 	output string
 	// max number of iterations
 	iters int
-	// training defines type of learning
-	training string
 	// restore mode
 	mode string
-)
+}
 
-func init() {
-	flag.StringVar(&datadir, "datadir", "", "Path to data pattern directory")
-	flag.StringVar(&input, "input", "", "Path to input data pattern")
-	flag.StringVar(&output, "output", "", "Path to output data pattern")
-	flag.StringVar(&mode, "mode", "async", "Restore pattern mode")
-	flag.IntVar(&iters, "iters", 1, "Max number of Hopfield net run iterations")
-	flag.StringVar(&training, "training", "hebbian", "Type of Hopfield Network training: hebbian or storkey")
+// infoConfig is info subcommand configuration
+type infoConfig struct {
+	// path to the saved model
+	model string
 }
 
-// parseCliFlags parses command line args
-func parseCliFlags() error {
-	flag.Parse()
+// usage prints cli usage
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [options]\n\n", cliname)
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  train\tTrain Hopfield network on data patterns and save it\n")
+	fmt.Fprintf(os.Stderr, "  restore\tRestore data pattern from saved Hopfield network\n")
+	fmt.Fprintf(os.Stderr, "  info\tPrint saved Hopfield network information\n\n")
+	fmt.Fprintf(os.Stderr, "Run '%s <command> -h' to see command options\n", cliname)
+}
 
-	if datadir == "" {
-		return fmt.Errorf("invalid path to data directory supplied: %s", datadir)
+// parseTrainFlags parses train subcommand args
+func parseTrainFlags(args []string) (*trainConfig, error) {
+	c := &trainConfig{}
+	fs := flag.NewFlagSet("train", flag.ContinueOnError)
+	fs.StringVar(&c.datadir, "datadir", "", "Path to data pattern directory")
+	fs.StringVar(&c.training, "training", "hebbian", "Type of Hopfield Network training: hebbian or storkey")
+	fs.StringVar(&c.model, "model", "", "Path to the saved model")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
 	}
 
-	if output == "" {
-		return fmt.Errorf("invalid output path supplied: %s", output)
+	if c.datadir == "" {
+		return nil, fmt.Errorf("invalid path to data directory supplied: %s", c.datadir)
 	}
 
-	if iters <= 0 {
-		return fmt.Errorf("invalid max number of iterations: %d", iters)
+	if c.model == "" {
+		return nil, fmt.Errorf("invalid model path supplied: %s", c.model)
 	}
 
-	return nil
+	return c, nil
+}
+
+// parseRestoreFlags parses restore subcommand args
+func parseRestoreFlags(args []string) (*restoreConfig, error) {
+	c := &restoreConfig{}
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.StringVar(&c.model, "model", "", "Path to the saved model")
+	fs.StringVar(&c.datadir, "datadir", "", "Path to data pattern directory used to generate noisy input if no input is supplied")
+	fs.StringVar(&c.input, "input", "", "Path to input data pattern")
+	fs.StringVar(&c.output, "output", "", "Path to output data pattern")
+	fs.StringVar(&c.mode, "mode", "async", "Restore pattern mode: sync, async, sequential or greedy")
+	fs.IntVar(&c.iters, "iters", 1, "Max number of Hopfield net run iterations")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if c.model == "" {
+		return nil, fmt.Errorf("invalid model path supplied: %s", c.model)
+	}
+
+	if c.input == "" && c.datadir == "" {
+		return nil, fmt.Errorf("either input or data directory must be supplied")
+	}
+
+	if c.output == "" {
+		return nil, fmt.Errorf("invalid output path supplied: %s", c.output)
+	}
+
+	if c.iters <= 0 {
+		return nil, fmt.Errorf("invalid max number of iterations: %d", c.iters)
+	}
+
+	return c, nil
+}
+
+// parseInfoFlags parses info subcommand args
+func parseInfoFlags(args []string) (*infoConfig, error) {
+	c := &infoConfig{}
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	fs.StringVar(&c.model, "model", "", "Path to the saved model")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if c.model == "" {
+		return nil, fmt.Errorf("invalid model path supplied: %s", c.model)
+	}
+
+	return c, nil
 }
 
 // readImage reads an image file in path and returns it as image.Image or fails with error
@@ -97,23 +167,16 @@ func saveImage(path string, img image.Image) error {
 	return fmt.Errorf("Unsupported image format: %s", filepath.Ext(path))
 }
 
-func main() {
-	// exit if incorrect cli params were passed in
-	if err := parseCliFlags(); err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
-		os.Exit(1)
-	}
-
+// readPatterns reads patterns from image files in datadir
+func readPatterns(datadir string) ([]*hopfield.Pattern, error) {
 	// read datadir from supplied directory
 	files, err := ioutil.ReadDir(datadir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
-		os.Exit(1)
+		return nil, err
 	}
 
 	if len(files) == 0 {
-		fmt.Fprintf(os.Stderr, "\nERROR: No patterns found in %s\n", datadir)
-		os.Exit(1)
+		return nil, fmt.Errorf("No patterns found in %s", datadir)
 	}
 
 	// read in Hopfield network patterns from data files in datadir
@@ -121,59 +184,183 @@ func main() {
 	for i := range files {
 		img, err := readImage(path.Join(datadir, files[i].Name()))
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
-			os.Exit(1)
+			return nil, err
 		}
 		// convert Image to Pattern
 		patterns[i] = hopfield.Image2Pattern(img)
 	}
 
+	return patterns, nil
+}
+
+// train trains Hopfield network on patterns in datadir and saves it
+func train(c *trainConfig) error {
+	patterns, err := readPatterns(c.datadir)
+	if err != nil {
+		return err
+	}
+
 	// Create new Hopfield Network and set its size to the length of the read pattern
-	n, err := hopfield.NewNetwork(patterns[0].Len(), training)
+	n, err := hopfield.NewNetwork(patterns[0].Len(), c.training)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
-		os.Exit(1)
+		return err
 	}
 
 	// store patterns in Hopfield network
 	if err := n.Store(patterns); err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
-		os.Exit(1)
+		return err
+	}
+
+	return n.Save(c.model)
+}
+
+// restore restores pattern from saved Hopfield network
+func restore(c *restoreConfig) error {
+	n, err := hopfield.Load(c.model)
+	if err != nil {
+		return err
 	}
 
 	var resPattern *hopfield.Pattern
 	// if no input is passed it we will generate our own noisy data
-	if input == "" {
+	if c.input == "" {
+		patterns, err := readPatterns(c.datadir)
+		if err != nil {
+			return err
+		}
 		// add some noise into one of the patterns
-		noisyPattern := hopfield.AddNoise(patterns[1], 20)
+		noisyPattern := hopfield.AddNoise(patterns[len(patterns)-1], 20)
 		//encode pattern into Gray Image
 		img := hopfield.Pattern2Image(noisyPattern, image.Rect(0, 0, width, height))
 		// save the noisy image for reference
 		if err := saveImage("noisy.png", img); err != nil {
-			fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
-			os.Exit(1)
+			return err
 		}
 		resPattern = noisyPattern
 	} else {
-		img, err := readImage(input)
+		img, err := readImage(c.input)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
-			os.Exit(1)
+			return err
 		}
 		// convert Image to Pattern
 		resPattern = hopfield.Image2Pattern(img)
 	}
 
+	rule, err := updateRule(c.mode)
+	if err != nil {
+		return err
+	}
+
 	// restore image from Hopfield network
-	res, err := n.Restore(resPattern, mode, iters)
+	res, err := n.RestoreRule(resPattern, rule, c.iters)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
-		os.Exit(1)
+		return err
 	}
 
 	// render the restored image
 	img := hopfield.Pattern2Image(res, image.Rect(0, 0, width, height))
-	if err := saveImage(output, img); err != nil {
+
+	return saveImage(c.output, img)
+}
+
+// updateRule returns Hopfield network update rule for restore mode
+func updateRule(mode string) (hopfield.UpdateRule, error) {
+	switch mode {
+	case "sync":
+		return hopfield.Synchronous, nil
+	case "async":
+		return hopfield.AsyncRandom, nil
+	case "sequential":
+		return hopfield.AsyncSequential, nil
+	case "greedy":
+		return hopfield.Greedy, nil
+	}
+
+	return 0, fmt.Errorf("unsupported mode: %s", mode)
+}
+
+// info prints saved Hopfield network information
+func info(c *infoConfig) error {
+	n, err := hopfield.Load(c.model)
+	if err != nil {
+		return err
+	}
+
+	size, _ := n.Weights().Dims()
+	min, max, mean := weightStats(n)
+	fmt.Printf("Size:\t\t%d\n", size)
+	fmt.Printf("Method:\t\t%s\n", n.Method())
+	fmt.Printf("Capacity:\t%d\n", n.Capacity())
+	fmt.Printf("Memorised:\t%d\n", n.Memorised())
+	fmt.Printf("Weights:\tmin %f, max %f, mean %f\n", min, max, mean)
+
+	return nil
+}
+
+// weightStats returns min, max and mean of the off-diagonal network weights
+func weightStats(n *hopfield.Network) (min, max, mean float64) {
+	w := n.Weights()
+	size, _ := w.Dims()
+	if size < 2 {
+		return 0, 0, 0
+	}
+
+	min, max = w.At(0, 1), w.At(0, 1)
+	count := 0
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			v := w.At(i, j)
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+			mean += v
+			count++
+		}
+	}
+
+	return min, max, mean / float64(count)
+}
+
+// run runs the subcommand in args
+func run(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("no command supplied")
+	}
+
+	switch args[0] {
+	case "train":
+		c, err := parseTrainFlags(args[1:])
+		if err != nil {
+			return err
+		}
+		return train(c)
+	case "restore":
+		c, err := parseRestoreFlags(args[1:])
+		if err != nil {
+			return err
+		}
+		return restore(c)
+	case "info":
+		c, err := parseInfoFlags(args[1:])
+		if err != nil {
+			return err
+		}
+		return info(c)
+	case "-h", "-help", "--help", "help":
+		usage()
+		return nil
+	}
+
+	usage()
+	return fmt.Errorf("unknown command: %s", args[0])
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "\nERROR: %s\n", err)
 		os.Exit(1)
 	}
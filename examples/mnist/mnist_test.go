@@ -0,0 +1,117 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/milosgajdos/gopfield/hopfield"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrainFlags(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := parseTrainFlags([]string{"-datadir", "patterns", "-model", "net.gob", "-training", "storkey"})
+	assert.NoError(err)
+	assert.Equal("patterns", c.datadir)
+	assert.Equal("net.gob", c.model)
+	assert.Equal("storkey", c.training)
+
+	// training defaults to hebbian
+	c, err = parseTrainFlags([]string{"-datadir", "patterns", "-model", "net.gob"})
+	assert.NoError(err)
+	assert.Equal("hebbian", c.training)
+
+	// missing data directory
+	c, err = parseTrainFlags([]string{"-model", "net.gob"})
+	assert.Nil(c)
+	assert.Error(err)
+
+	// missing model path
+	c, err = parseTrainFlags([]string{"-datadir", "patterns"})
+	assert.Nil(c)
+	assert.Error(err)
+
+	// unknown flag
+	c, err = parseTrainFlags([]string{"-foo", "bar"})
+	assert.Nil(c)
+	assert.Error(err)
+}
+
+func TestParseRestoreFlags(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := parseRestoreFlags([]string{"-model", "net.gob", "-input", "in.png", "-output", "out.png", "-mode", "sync", "-iters", "5"})
+	assert.NoError(err)
+	assert.Equal("net.gob", c.model)
+	assert.Equal("in.png", c.input)
+	assert.Equal("out.png", c.output)
+	assert.Equal("sync", c.mode)
+	assert.Equal(5, c.iters)
+
+	// defaults
+	c, err = parseRestoreFlags([]string{"-model", "net.gob", "-datadir", "patterns", "-output", "out.png"})
+	assert.NoError(err)
+	assert.Equal("async", c.mode)
+	assert.Equal(1, c.iters)
+
+	// missing model path
+	c, err = parseRestoreFlags([]string{"-input", "in.png", "-output", "out.png"})
+	assert.Nil(c)
+	assert.Error(err)
+
+	// neither input nor data directory
+	c, err = parseRestoreFlags([]string{"-model", "net.gob", "-output", "out.png"})
+	assert.Nil(c)
+	assert.Error(err)
+
+	// missing output path
+	c, err = parseRestoreFlags([]string{"-model", "net.gob", "-input", "in.png"})
+	assert.Nil(c)
+	assert.Error(err)
+
+	// invalid number of iterations
+	c, err = parseRestoreFlags([]string{"-model", "net.gob", "-input", "in.png", "-output", "out.png", "-iters", "0"})
+	assert.Nil(c)
+	assert.Error(err)
+}
+
+func TestParseInfoFlags(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := parseInfoFlags([]string{"-model", "net.gob"})
+	assert.NoError(err)
+	assert.Equal("net.gob", c.model)
+
+	// missing model path
+	c, err = parseInfoFlags([]string{})
+	assert.Nil(c)
+	assert.Error(err)
+}
+
+func TestUpdateRule(t *testing.T) {
+	assert := assert.New(t)
+
+	rule, err := updateRule("sync")
+	assert.NoError(err)
+	assert.Equal(hopfield.Synchronous, rule)
+
+	rule, err = updateRule("greedy")
+	assert.NoError(err)
+	assert.Equal(hopfield.Greedy, rule)
+
+	_, err = updateRule("foobar")
+	assert.Error(err)
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	err := run([]string{})
+	assert.Error(err)
+
+	err = run([]string{"foo"})
+	assert.Error(err)
+
+	err = run([]string{"info"})
+	assert.Error(err)
+}
@@ -0,0 +1,129 @@
+package hopfield
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// cacheKey identifies cached restore result
+type cacheKey struct {
+	// hash is the input pattern hash
+	hash uint64
+	// mode is restore mode
+	mode string
+	// iters is number of restore iterations
+	iters int
+}
+
+// cacheEntry is cached restore result
+type cacheEntry struct {
+	key cacheKey
+	// input is the restored pattern
+	input *Pattern
+	// output is the restore result
+	output *Pattern
+}
+
+// CachedNetwork is Hopfield network which memoizes restore results in LRU cache keyed by the input pattern hash.
+// CachedNetwork is not safe for concurrent use.
+type CachedNetwork struct {
+	// n is the cached network
+	n *Network
+	// size is the maximum number of cached results
+	size int
+	// ll orders cache entries from the most to the least recently used
+	ll *list.List
+	// cache maps cache keys to their entries
+	cache map[cacheKey]*list.Element
+}
+
+// NewCachedNetwork creates new Hopfield network which caches up to cacheSize restore results and returns it.
+// Network can be further configured via options.
+// NewCachedNetwork returns error if non-positive cache size is supplied. Otherwise it returns the same errors as NewNetwork.
+func NewCachedNetwork(size int, method string, cacheSize int, opts ...Option) (*CachedNetwork, error) {
+	// cache must be able to hold at least one result
+	if cacheSize <= 0 {
+		return nil, fmt.Errorf("invalid cache size: %d", cacheSize)
+	}
+	n, err := NewNetwork(size, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachedNetwork{
+		n:     n,
+		size:  cacheSize,
+		ll:    list.New(),
+		cache: make(map[cacheKey]*list.Element),
+	}, nil
+}
+
+// Capacity returns network capacity
+func (c *CachedNetwork) Capacity() int {
+	return c.n.Capacity()
+}
+
+// Memorised returns count of memorised patterns
+func (c *CachedNetwork) Memorised() int {
+	return c.n.Memorised()
+}
+
+// Store stores supplied patterns in network. Storing patterns changes network weights, so Store purges the cache.
+// It returns the same errors as Network.Store.
+func (c *CachedNetwork) Store(patterns []*Pattern) error {
+	if err := c.n.Store(patterns); err != nil {
+		return err
+	}
+	c.ll.Init()
+	c.cache = make(map[cacheKey]*list.Element)
+
+	return nil
+}
+
+// Restore tries to restore supplied pattern from network through mode restore process and returns it.
+// If the same pattern has been restored before in the same mode and with the same number of iterations,
+// the cached result is returned without running the network. Unlike Network.Restore, Restore does not
+// modify the supplied pattern and always returns a new pattern.
+// It returns the same errors as Network.Restore.
+func (c *CachedNetwork) Restore(p *Pattern, mode string, iters int) (*Pattern, error) {
+	if p == nil {
+		return c.n.Restore(p, mode, iters)
+	}
+	key := cacheKey{hash: p.Hash(), mode: mode, iters: iters}
+	if e, ok := c.cache[key]; ok {
+		entry := e.Value.(*cacheEntry)
+		// guard against hash collisions
+		if entry.input.equal(p) {
+			c.ll.MoveToFront(e)
+			return entry.output.clone(), nil
+		}
+	}
+	res, err := c.n.Restore(p.clone(), mode, iters)
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, p.clone(), res.clone())
+
+	return res, nil
+}
+
+// Energy calculates Hopfield network energy for a given pattern and returns it.
+// It returns the same errors as Network.Energy.
+func (c *CachedNetwork) Energy(p *Pattern) (float64, error) {
+	return c.n.Energy(p)
+}
+
+// add adds restore result to the cache evicting the least recently used result if the cache is full
+func (c *CachedNetwork) add(key cacheKey, input, output *Pattern) {
+	if e, ok := c.cache[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value = &cacheEntry{key: key, input: input, output: output}
+		return
+	}
+	c.cache[key] = c.ll.PushFront(&cacheEntry{key: key, input: input, output: output})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.cache, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,82 @@
+package hopfield
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCachedNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCachedNetwork(4, "hebbian", 2)
+	assert.NotNil(c)
+	assert.NoError(err)
+
+	errString := "invalid cache size: %d"
+	cacheSize := 0
+	c, err = NewCachedNetwork(4, "hebbian", cacheSize)
+	assert.Nil(c)
+	assert.EqualError(err, fmt.Sprintf(errString, cacheSize))
+
+	errString = "unsupported training method: %s"
+	method := "foobar"
+	c, err = NewCachedNetwork(4, method, 2)
+	assert.Nil(c)
+	assert.EqualError(err, fmt.Sprintf(errString, method))
+}
+
+func TestCachedRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewCachedNetwork(4, "hebbian", 2)
+	assert.NotNil(c)
+	assert.NoError(err)
+
+	stored := []float64{1.0, -1.0, -1.0, 1.0}
+	err = c.Store([]*Pattern{Encode(stored)})
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := c.Restore(pattern, "async", 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	// miss
+	input := Encode([]float64{1.0, -1.0, -1.0, -1.0})
+	res, err = c.Restore(input, "async", 10)
+	assert.NoError(err)
+	assert.Equal(stored, res.RawData())
+	assert.Equal([]float64{1.0, -1.0, -1.0, -1.0}, input.RawData())
+	assert.Equal(1, c.ll.Len())
+
+	// hit returns a copy of the cached result
+	res.RawData()[0] = -1.0
+	hit, err := c.Restore(input, "async", 10)
+	assert.NoError(err)
+	assert.Equal(stored, hit.RawData())
+	assert.Equal(1, c.ll.Len())
+
+	// different mode is a miss
+	_, err = c.Restore(input, "sync", 10)
+	assert.NoError(err)
+	assert.Equal(2, c.ll.Len())
+
+	// full cache evicts the least recently used result
+	other := Encode([]float64{-1.0, -1.0, -1.0, 1.0})
+	_, err = c.Restore(other, "async", 10)
+	assert.NoError(err)
+	assert.Equal(2, c.ll.Len())
+	_, ok := c.cache[cacheKey{hash: input.Hash(), mode: "async", iters: 10}]
+	assert.False(ok)
+	_, ok = c.cache[cacheKey{hash: input.Hash(), mode: "sync", iters: 10}]
+	assert.True(ok)
+
+	// storing patterns purges the cache
+	err = c.Store([]*Pattern{Encode([]float64{1.0, 1.0, -1.0, -1.0})})
+	assert.NoError(err)
+	assert.Equal(0, c.ll.Len())
+	assert.Empty(c.cache)
+}
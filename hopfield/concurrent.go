@@ -0,0 +1,94 @@
+package hopfield
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// ConcurrentNetwork is Hopfield network which is safe for concurrent use by multiple goroutines.
+// Patterns can be restored concurrently, whilst storing patterns is serialized with all other operations.
+type ConcurrentNetwork struct {
+	// mu guards the network
+	mu sync.RWMutex
+	// n is the guarded network
+	n *Network
+}
+
+// NewConcurrentNetwork creates new concurrency safe Hopfield network which is trained using the training method and returns it.
+// Network can be further configured via options.
+// NewConcurrentNetwork returns the same errors as NewNetwork.
+func NewConcurrentNetwork(size int, method string, opts ...Option) (*ConcurrentNetwork, error) {
+	n, err := NewNetwork(size, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConcurrentNetwork{
+		n: n,
+	}, nil
+}
+
+// Capacity returns network capacity
+func (c *ConcurrentNetwork) Capacity() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.n.Capacity()
+}
+
+// Memorised returns count of memorised patterns
+func (c *ConcurrentNetwork) Memorised() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.n.Memorised()
+}
+
+// Store stores supplied patterns in network.
+// Store blocks until all the running restores have finished.
+// It returns the same errors as Network.Store.
+func (c *ConcurrentNetwork) Store(patterns []*Pattern) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.n.Store(patterns)
+}
+
+// Restore tries to restore supplied pattern from network through mode restore process and returns it.
+// Each call uses its own pseudorandom number generator so concurrent async restores do not share any state.
+// If the network was created with WithSeed, the generator is seeded from the network generator, so sequences
+// of restores are reproducible; the order of concurrent restores is not, though.
+// Restore modifies the supplied pattern in place, so the same pattern must not be restored concurrently.
+// It returns the same errors as Network.Restore.
+func (c *ConcurrentNetwork) Restore(p *Pattern, mode string, iters int) (*Pattern, error) {
+	rng := rand.New(rand.NewSource(c.seed()))
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.n.restore(p, mode, iters, rng)
+}
+
+// Energy calculates Hopfield network energy for a given pattern and returns it.
+// Energies can be calculated concurrently with restores and they block while patterns are being stored.
+// It returns the same errors as Network.Energy.
+func (c *ConcurrentNetwork) Energy(p *Pattern) (float64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.n.Energy(p)
+}
+
+// seed returns the seed of the pseudorandom number generator of a single restore.
+// It's drawn from the network generator if the network is seeded, otherwise from global source.
+func (c *ConcurrentNetwork) seed() int64 {
+	// network generator is not safe for concurrent use
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.n.rng == nil {
+		return rand.Int63()
+	}
+
+	return c.n.rng.Int63()
+}
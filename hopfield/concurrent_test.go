@@ -0,0 +1,118 @@
+package hopfield
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConcurrentNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	method := "hebbian"
+	size := 5
+	c, err := NewConcurrentNetwork(size, method)
+	assert.NotNil(c)
+	assert.NoError(err)
+
+	errString := "invalid network size: %d"
+	size = -2
+	c, err = NewConcurrentNetwork(size, method)
+	assert.Nil(c)
+	assert.EqualError(err, fmt.Sprintf(errString, size))
+}
+
+func TestConcurrentRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewConcurrentNetwork(4, "hebbian")
+	assert.NotNil(c)
+	assert.NoError(err)
+
+	stored := []float64{1.0, -1.0, -1.0, 1.0}
+	err = c.Store([]*Pattern{Encode(stored)})
+	assert.NoError(err)
+
+	workers := 50
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			p := Encode([]float64{1.0, -1.0, -1.0, -1.0})
+			// keep storing patterns while the other goroutines restore
+			if i%10 == 0 {
+				assert.NoError(c.Store([]*Pattern{Encode([]float64{1.0, -1.0, -1.0, 1.0})}))
+			}
+			_, err := c.Energy(p)
+			assert.NoError(err)
+			res, err := c.Restore(p, "async", 10)
+			assert.NoError(err)
+			assert.Equal(stored, res.RawData())
+			assert.True(c.Memorised() >= 0)
+			assert.True(c.Capacity() > 0)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentRestoreSeed(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	patterns := randomPatterns(12, size, rand.New(rand.NewSource(1)))
+	// restores draw randomness from the network generator seeded with the same seed
+	restore := func() [][]float64 {
+		c, err := NewConcurrentNetwork(size, "hebbian", WithSeed(1))
+		assert.NotNil(c)
+		assert.NoError(err)
+		err = c.Store(patterns)
+		assert.NoError(err)
+
+		var results [][]float64
+		noise := rand.New(rand.NewSource(2))
+		for _, p := range patterns {
+			res, err := c.Restore(c.n.addNoise(p.clone(), 30, noise), "async", 2)
+			assert.NoError(err)
+			results = append(results, res.RawData())
+		}
+		return results
+	}
+	assert.Equal(restore(), restore())
+}
+
+func TestConcurrentEnergy(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 20
+	c, err := NewConcurrentNetwork(size, "storkey")
+	assert.NotNil(c)
+	assert.NoError(err)
+
+	patterns := randomPatterns(10, size, rand.New(rand.NewSource(1)))
+	p := patterns[0].clone()
+	orig := p.clone()
+
+	workers := 20
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			// store patterns while the other goroutines calculate energies of the shared pattern
+			if i%2 == 0 {
+				assert.NoError(c.Store(patterns[i/2 : i/2+1]))
+				return
+			}
+			_, err := c.Energy(p)
+			assert.NoError(err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(orig.RawData(), p.RawData())
+	assert.Equal(len(patterns), c.Memorised())
+}
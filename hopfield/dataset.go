@@ -0,0 +1,143 @@
+package hopfield
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxPatternSize is the largest dimension of the patterns read by ReadPatterns.
+// It guards against allocating huge patterns when reading corrupted or untrusted inputs.
+const maxPatternSize = 1 << 20
+
+// ReadPatternsCSVGz reads patterns from gzip compressed text file in path and returns them.
+// The decompressed file must contain one pattern per line. Pattern values are separated
+// either by commas or by whitespace. Values are encoded into patterns using Encode.
+// Patterns in the binary format written by WritePatterns are read by ReadPatterns.
+// It returns error if the file can't be read or decompressed, if any of the values can't be parsed,
+// if the patterns do not have the same dimension or if the file does not contain any patterns.
+func ReadPatternsCSVGz(path string) ([]*Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	return parsePatterns(gz)
+}
+
+// parsePatterns parses newline delimited patterns from r and returns them
+func parsePatterns(r io.Reader) ([]*Pattern, error) {
+	var patterns []*Pattern
+	scanner := bufio.NewScanner(r)
+	// image patterns can have very long lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		// skip empty lines
+		if text == "" {
+			continue
+		}
+		var fields []string
+		if strings.Contains(text, ",") {
+			fields = strings.Split(text, ",")
+		} else {
+			fields = strings.Fields(text)
+		}
+		data := make([]float64, len(fields))
+		for i := range fields {
+			val, err := strconv.ParseFloat(strings.TrimSpace(fields[i]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern value on line %d: %s", line, fields[i])
+			}
+			data[i] = val
+		}
+		// all patterns must have the same dimension
+		if len(patterns) > 0 && len(data) != patterns[0].Len() {
+			return nil, fmt.Errorf("invalid pattern dimension on line %d: %d", line, len(data))
+		}
+		patterns = append(patterns, Encode(data))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no patterns found")
+	}
+
+	return patterns, nil
+}
+
+// WritePatterns writes patterns to w in a compact binary format. The number of patterns is written first,
+// followed by the patterns. Each pattern is written as its length followed by its values packed by Pack.
+// Lengths are written as little endian uint32 numbers. Only the sign of pattern values is preserved.
+// It returns error if any of the patterns is nil or empty or if the patterns fail to be written to w.
+func WritePatterns(w io.Writer, patterns []*Pattern) error {
+	for _, p := range patterns {
+		// pattern can't be nil or empty
+		if p == nil || p.Len() == 0 {
+			return fmt.Errorf("invalid pattern supplied: %v", p)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	if err := binary.Write(bw, binary.LittleEndian, uint32(len(patterns))); err != nil {
+		return err
+	}
+	for _, p := range patterns {
+		if err := binary.Write(bw, binary.LittleEndian, uint32(p.Len())); err != nil {
+			return err
+		}
+		if _, err := bw.Write(p.Pack()); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadPatterns reads patterns in the packed binary format written by WritePatterns from r and returns them.
+// Patterns in gzip compressed text files are read by ReadPatternsCSVGz.
+// It returns error if r can't be read or if it does not contain patterns written by WritePatterns.
+// Patterns with more than maxPatternSize values are rejected as invalid.
+func ReadPatterns(r io.Reader) ([]*Pattern, error) {
+	br := bufio.NewReader(r)
+	var count uint32
+	if err := binary.Read(br, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+	var patterns []*Pattern
+	for i := uint32(0); i < count; i++ {
+		var size uint32
+		if err := binary.Read(br, binary.LittleEndian, &size); err != nil {
+			return nil, err
+		}
+		// pattern can't be empty or exceed the maximum size
+		if size == 0 || size > maxPatternSize {
+			return nil, fmt.Errorf("invalid pattern dimension: %d", size)
+		}
+		bits := make([]byte, (int(size)+7)/8)
+		if _, err := io.ReadFull(br, bits); err != nil {
+			return nil, err
+		}
+		p, err := Unpack(bits, int(size))
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+
+	return patterns, nil
+}
@@ -0,0 +1,145 @@
+package hopfield
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeGz writes gzip compressed data to a file in path
+func writeGz(path, data string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(data)); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+func TestReadPatternsCSVGz(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "gopfield")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	patterns, err := ReadPatternsCSVGz(filepath.Join(dir, "foobar.gz"))
+	assert.Nil(patterns)
+	assert.Error(err)
+
+	path := filepath.Join(dir, "patterns.gz")
+	err = os.WriteFile(path, []byte("1,-1"), 0600)
+	assert.NoError(err)
+	patterns, err = ReadPatternsCSVGz(path)
+	assert.Nil(patterns)
+	assert.Error(err)
+
+	testCases := []struct {
+		data     string
+		expected [][]float64
+		err      string
+	}{
+		{"1,-1,-1,1\n-1, 1, 1, 0\n", [][]float64{{1, -1, -1, 1}, {-1, 1, 1, -1}}, ""},
+		{"1 -1 -1 1\n\n0.5\t-3 2 1\n", [][]float64{{1, -1, -1, 1}, {1, -1, 1, 1}}, ""},
+		{"1,-1\n1,foo\n", nil, fmt.Sprintf("invalid pattern value on line %d: %s", 2, "foo")},
+		{"1,-1\n1,-1,1\n", nil, fmt.Sprintf("invalid pattern dimension on line %d: %d", 2, 3)},
+		{"\n\n", nil, "no patterns found"},
+	}
+
+	for _, tc := range testCases {
+		err = writeGz(path, tc.data)
+		assert.NoError(err)
+
+		patterns, err = ReadPatternsCSVGz(path)
+		if tc.err != "" {
+			assert.Nil(patterns)
+			assert.EqualError(err, tc.err)
+			continue
+		}
+		assert.NoError(err)
+		assert.Len(patterns, len(tc.expected))
+		for i := range patterns {
+			assert.Equal(tc.expected[i], patterns[i].RawData())
+		}
+	}
+}
+
+func TestWriteReadPatterns(t *testing.T) {
+	assert := assert.New(t)
+
+	rng := rand.New(rand.NewSource(1))
+	var patterns []*Pattern
+	for _, size := range []int{1, 7, 8, 9, 64, 100} {
+		patterns = append(patterns, randomPatterns(1, size, rng)...)
+	}
+
+	var buf bytes.Buffer
+	err := WritePatterns(&buf, patterns)
+	assert.NoError(err)
+	// count, lengths and packed values
+	assert.Equal(4+6*4+1+1+1+2+8+13, buf.Len())
+
+	read, err := ReadPatterns(&buf)
+	assert.NoError(err)
+	assert.Len(read, len(patterns))
+	for i := range patterns {
+		assert.Equal(patterns[i].RawData(), read[i].RawData())
+	}
+
+	// no patterns
+	buf.Reset()
+	err = WritePatterns(&buf, nil)
+	assert.NoError(err)
+	read, err = ReadPatterns(&buf)
+	assert.NoError(err)
+	assert.Len(read, 0)
+
+	var pattern *Pattern
+	err = WritePatterns(&buf, []*Pattern{pattern})
+	assert.EqualError(err, fmt.Sprintf("invalid pattern supplied: %v", pattern))
+
+	// truncated data
+	buf.Reset()
+	err = WritePatterns(&buf, patterns)
+	assert.NoError(err)
+	read, err = ReadPatterns(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Nil(read)
+	assert.Error(err)
+
+	// empty pattern
+	read, err = ReadPatterns(bytes.NewReader([]byte{1, 0, 0, 0, 0, 0, 0, 0}))
+	assert.Nil(read)
+	assert.EqualError(err, fmt.Sprintf("invalid pattern dimension: %d", 0))
+
+	// oversized pattern
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header, 1)
+	binary.LittleEndian.PutUint32(header[4:], maxPatternSize+1)
+	read, err = ReadPatterns(bytes.NewReader(header))
+	assert.Nil(read)
+	assert.EqualError(err, fmt.Sprintf("invalid pattern dimension: %d", maxPatternSize+1))
+
+	// truncated header
+	read, err = ReadPatterns(bytes.NewReader(header[:6]))
+	assert.Nil(read)
+	assert.Error(err)
+
+	// header without pattern values
+	binary.LittleEndian.PutUint32(header[4:], maxPatternSize)
+	read, err = ReadPatterns(bytes.NewReader(header))
+	assert.Nil(read)
+	assert.Error(err)
+}
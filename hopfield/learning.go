@@ -0,0 +1,84 @@
+package hopfield
+
+import (
+	"strings"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LearningRule is custom Hopfield network learning rule.
+// It updates network weights w in place to store patterns in the network.
+type LearningRule func(w *mat.SymDense, patterns []*Pattern)
+
+var (
+	rulesMu sync.RWMutex
+	// rules are registered custom learning rules
+	rules = make(map[string]LearningRule)
+)
+
+// RegisterLearningRule registers custom learning rule fn under name, so the networks can be trained using it.
+// Names are case insensitive. Registering rule under the same name again replaces the registered rule.
+// RegisterLearningRule panics if name is empty, if it is the name of a built-in rule or if fn is nil.
+func RegisterLearningRule(name string, fn LearningRule) {
+	name = strings.ToLower(name)
+	if name == "" {
+		panic("hopfield: empty learning rule name")
+	}
+	if builtinRule(name) {
+		panic("hopfield: can't register built-in learning rule " + name)
+	}
+	if fn == nil {
+		panic("hopfield: nil learning rule " + name)
+	}
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[name] = fn
+}
+
+// learningRule returns custom learning rule registered under name
+func learningRule(name string) (LearningRule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	fn, ok := rules[strings.ToLower(name)]
+
+	return fn, ok
+}
+
+// builtinRule returns true if method is one of the built-in learning rules
+func builtinRule(method string) bool {
+	return strings.EqualFold("hebbian", method) || strings.EqualFold("storkey", method)
+}
+
+// supportedMethod returns true if method is either built-in or registered learning rule
+func supportedMethod(method string) bool {
+	if builtinRule(method) {
+		return true
+	}
+	_, ok := learningRule(method)
+
+	return ok
+}
+
+// storkey stores patterns in zeroed weights matrix w using Storkey learning. The patterns are stored one by one:
+// local fields h of the pattern neurons are computed from the weights of the previously stored patterns and
+// the weights are then updated by the outer products of the pattern and its local fields.
+// h is scratch local fields vector of the same dimension as the patterns.
+func storkey(w *mat.SymDense, h *mat.VecDense, patterns []*Pattern) {
+	dim := patterns[0].Len()
+	for _, p := range patterns {
+		// local fields are computed from the weights before the pattern is stored
+		h.MulVec(w, p.Vec())
+		// we only traverse higher triangular matrix because we are using Symmetric matrix
+		for i := 0; i < dim; i++ {
+			for j := i + 1; j < dim; j++ {
+				wij := w.At(i, j)
+				// Storkey local fields exclude i-th and j-th neuron; diagonal weights are always zero
+				hij := h.AtVec(i) - wij*p.At(j)
+				hji := h.AtVec(j) - wij*p.At(i)
+				sum := p.At(i)*p.At(j) - p.At(i)*hji - hij*p.At(j)
+				w.SetSym(i, j, wij+sum/float64(dim))
+			}
+		}
+	}
+}
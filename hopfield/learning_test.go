@@ -0,0 +1,49 @@
+package hopfield
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRegisterLearningRule(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "constant")
+	assert.Nil(n)
+	assert.EqualError(err, "unsupported training method: constant")
+
+	calls := 0
+	RegisterLearningRule("Constant", func(w *mat.SymDense, patterns []*Pattern) {
+		calls++
+		dim := w.Symmetric()
+		for i := 0; i < dim; i++ {
+			for j := i + 1; j < dim; j++ {
+				w.SetSym(i, j, w.At(i, j)+float64(len(patterns)))
+			}
+		}
+	})
+
+	n, err = NewNetwork(size, "constant")
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.Equal("constant", n.Method())
+
+	patterns := []*Pattern{
+		Encode([]float64{1.0, -1.0, 1.0, -1.0}),
+		Encode([]float64{-1.0, 1.0, 1.0, -1.0}),
+	}
+	err = n.Store(patterns)
+	assert.NoError(err)
+	assert.Equal(1, calls)
+	assert.Equal(2, n.Memorised())
+	assert.Equal(2.0, n.Weights().At(0, 1))
+	assert.Equal(0.0, n.Weights().At(0, 0))
+
+	// built-in rules can't be replaced
+	assert.Panics(func() { RegisterLearningRule("hebbian", func(*mat.SymDense, []*Pattern) {}) })
+	assert.Panics(func() { RegisterLearningRule("", func(*mat.SymDense, []*Pattern) {}) })
+	assert.Panics(func() { RegisterLearningRule("nil", nil) })
+}
@@ -1,45 +1,142 @@
 package hopfield
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"strings"
+	"time"
 
 	"gonum.org/v1/gonum/mat"
 )
 
+var (
+	// ErrDiverged is returned when network energy keeps increasing during restore
+	ErrDiverged = errors.New("network diverged")
+	// ErrUntrained is returned when restoring pattern from network which has not memorised any patterns
+	ErrUntrained = errors.New("network is not trained")
+	// ErrEmptyPattern is returned when pattern without any values is supplied
+	ErrEmptyPattern = errors.New("empty pattern supplied")
+	// ErrVersionMismatch is returned when loading network saved in unsupported version of the format
+	ErrVersionMismatch = errors.New("unsupported network format version")
+)
+
 // Network is Hopfield network
 type Network struct {
 	// weights are network neurons weights
 	weights *mat.SymDense
 	// bias are network unit direct inputs
 	bias *mat.VecDense
+	// external is external field added to the local fields of network neurons
+	external *mat.VecDense
 	// method is training method
 	method string
 	// memorised keeps a count of memorized patterns
 	memorised int
+	// triState enables tri-state neurons
+	triState bool
+	// deadBand is the tri-state neuron dead band
+	deadBand float64
+	// on is the value of active neurons
+	on float64
+	// off is the value of inactive neurons
+	off float64
+	// divWindow is the number of sweeps watched by divergence guard
+	divWindow int
+	// divThreshold is the energy increase which triggers divergence guard
+	divThreshold float64
+	// remember enables remembering of stored patterns
+	remember bool
+	// remembered are copies of stored patterns
+	remembered []*Pattern
+	// normalize enables normalization of Hebbian weights by the number of stored patterns
+	normalize bool
+	// meanSubtract enables subtraction of mean neuron activity in Hebbian learning
+	meanSubtract bool
+	// activity keeps the sum of the states of each neuron over all stored patterns; it's used by covariance rule
+	activity []float64
+	// randomStart enables restoring from random initial state
+	randomStart bool
+	// rawAccess enables direct access to raw weights data in local fields
+	rawAccess bool
+	// src is the source of network pseudorandom number generator
+	src *source
+	// rng is network pseudorandom number generator; if nil, default source is used
+	rng *rand.Rand
+	// wBuf is scratch weights matrix reused across stores; it's allocated on the first store
+	wBuf *mat.SymDense
+	// hBuf is scratch local fields vector reused across stores; it's allocated on the first store
+	hBuf *mat.VecDense
 }
 
 // NewNetwork creates new Hopfield network which is trained using the training method and returns it.
-// NewNetwork returns error if either non-positive size is supplied or unsupported training method is supplied.
-func NewNetwork(size int, method string) (*Network, error) {
+// Network can be further configured via options.
+// Training method is either one of the built-in hebbian and storkey methods or a rule registered by RegisterLearningRule.
+// NewNetwork returns error if either non-positive size is supplied, unsupported training method is supplied
+// or if any of the options is invalid.
+func NewNetwork(size int, method string, opts ...Option) (*Network, error) {
 	// can't have negative number of weights
 	if size <= 0 {
 		return nil, fmt.Errorf("invalid network size: %d", size)
 	}
 	// if unsupported method is supplied we return error
-	if !strings.EqualFold("hebbian", method) && !strings.EqualFold("storkey", method) {
+	if !supportedMethod(method) {
 		return nil, fmt.Errorf("unsupported training method: %s", method)
 	}
+	options := Options{
+		On:  1.0,
+		Off: -1.0,
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+	// dead band can't be negative
+	if options.DeadBand < 0.0 {
+		return nil, fmt.Errorf("invalid dead band: %f", options.DeadBand)
+	}
+	// active neurons must have higher value than inactive ones
+	if options.On <= options.Off {
+		return nil, fmt.Errorf("invalid neuron states: %f, %f", options.On, options.Off)
+	}
+	// divergence guard can't watch negative number of sweeps or negative energy increase
+	if options.DivergenceWindow < 0 || options.DivergenceThreshold < 0.0 {
+		return nil, fmt.Errorf("invalid divergence guard: %d, %f", options.DivergenceWindow, options.DivergenceThreshold)
+	}
+	// size guard must be checked before allocating the weights
+	if options.MaxSize > 0 && size > options.MaxSize {
+		return nil, fmt.Errorf("network size %d exceeds maximum size %d", size, options.MaxSize)
+	}
 	// allocate weights and bias matrices
 	weights := mat.NewSymDense(size, nil)
 	bias := mat.NewVecDense(size, nil)
+	external := mat.NewVecDense(size, nil)
+	var src *source
+	var rng *rand.Rand
+	if options.Seeded {
+		src = newSource(options.Seed)
+		rng = rand.New(src)
+	}
 
 	return &Network{
-		weights: weights,
-		bias:    bias,
-		method:  method,
+		weights:      weights,
+		bias:         bias,
+		external:     external,
+		method:       strings.ToLower(method),
+		triState:     options.TriState,
+		deadBand:     options.DeadBand,
+		on:           options.On,
+		off:          options.Off,
+		divWindow:    options.DivergenceWindow,
+		divThreshold: options.DivergenceThreshold,
+		remember:     options.Remember,
+		normalize:    options.NormalizeByCount,
+		meanSubtract: options.MeanSubtract,
+		randomStart:  options.RandomStart,
+		rawAccess:    options.RawAccess,
+		src:          src,
+		rng:          rng,
 	}, nil
 }
 
@@ -48,21 +145,112 @@ func (n Network) Weights() mat.Matrix {
 	return n.weights
 }
 
+// RawWeights returns the raw data of network weights along with its stride. Weights are stored in the upper
+// triangle of a symmetric matrix: weight of the connection between neurons i <= j is data[i*stride+j]
+// and the elements below the diagonal are not used. The data is live and modifying it modifies the network
+// weights, so the caller is responsible for keeping the diagonal zero.
+func (n Network) RawWeights() ([]float64, int) {
+	raw := n.weights.RawSymmetric()
+
+	return raw.Data, raw.Stride
+}
+
 // Bias returns network bias
 func (n Network) Bias() mat.Matrix {
 	return n.bias
 }
 
+// BiasSlice returns a copy of network bias. Modifying the returned slice does not affect the network.
+func (n Network) BiasSlice() []float64 {
+	return mat.Col(nil, 0, n.bias)
+}
+
+// SetExternalField sets external field h of network neurons. External field is added to the local fields
+// of the neurons during restore and contributes -Σ hᵢpᵢ to the network energy, so it biases the network
+// towards the states aligned with it without retraining. Zero external field disables it.
+// It returns error if h does not have the same dimension as number of network neurons.
+func (n *Network) SetExternalField(h []float64) error {
+	// every neuron must have its external field
+	if len(h) != n.external.Len() {
+		return fmt.Errorf("invalid external field dimension: %d", len(h))
+	}
+	n.external.CopyVec(mat.NewVecDense(len(h), h))
+
+	return nil
+}
+
 // Capacity returns network capacity
 func (n Network) Capacity() int {
 	// c is a number of neurons
 	_, c := n.weights.Dims()
+
+	return int(math.Floor(capacity(c, n.method)))
+}
+
+// EffectiveCapacity estimates capacity of the network trained using the training method for the supplied patterns and returns it.
+// Network capacity assumes the stored patterns are uncorrelated. EffectiveCapacity discounts it by the mean absolute
+// pairwise overlap of the patterns, so the more correlated the patterns are, the lower the effective capacity is.
+// It returns 0 if patterns is nil or if any of the patterns is invalid.
+func EffectiveCapacity(patterns []*Pattern, method string) float64 {
+	overlaps, err := OverlapMatrix(patterns)
+	if err != nil {
+		return 0.0
+	}
+	count := overlaps.Symmetric()
+	// mean absolute overlap of distinct pairs of patterns
+	mean := 0.0
+	if count > 1 {
+		for i := 0; i < count; i++ {
+			for j := i + 1; j < count; j++ {
+				mean += math.Abs(overlaps.At(i, j))
+			}
+		}
+		mean /= float64(count*(count-1)) / 2
+	}
+
+	return capacity(patterns[0].Len(), method) * (1 - mean)
+}
+
+// MinSizeFor returns the smallest size of the network trained using the training method
+// whose capacity is at least the given number of patterns. Capacity grows with network size,
+// so the size is found by bisection. It returns 0 if patterns is non-positive.
+func MinSizeFor(patterns int, method string) int {
+	if patterns <= 0 {
+		return 0
+	}
+	fits := func(size int) bool {
+		return int(math.Floor(capacity(size, method))) >= patterns
+	}
+	// lo is too small, hi fits the patterns; capacity of a single neuron network is undefined
+	lo, hi := 1, 2
+	for !fits(hi) {
+		lo, hi = hi, 2*hi
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if fits(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi
+}
+
+// capacity returns capacity of the network of size neurons trained using the training method
+func capacity(size int, method string) float64 {
 	// storkey learning gives higher capacity
-	if strings.EqualFold("storkey", n.method) {
-		return int(math.Floor(float64(c) / (2 * math.Sqrt(math.Log(float64(c))))))
+	if strings.EqualFold("storkey", method) {
+		return float64(size) / (2 * math.Sqrt(math.Log(float64(size))))
 	}
 
-	return int(math.Floor(float64(c) / (2 * math.Log(float64(c)))))
+	return float64(size) / (2 * math.Log(float64(size)))
+}
+
+// Method returns network training method
+func (n Network) Method() string {
+	return n.method
 }
 
 // Memorised returns count of memorised patterns
@@ -70,9 +258,190 @@ func (n Network) Memorised() int {
 	return n.memorised
 }
 
+// Trained returns true if at least one pattern has been stored in the network
+func (n Network) Trained() bool {
+	return n.memorised > 0
+}
+
+// String returns a compact summary of the network: its size, training method, number of memorised patterns,
+// capacity and load, i.e. the ratio of memorised patterns to the number of neurons.
+func (n Network) String() string {
+	info := n.Info()
+
+	return fmt.Sprintf("Network{size: %d, method: %s, memorised: %d, capacity: %d, load: %.3f}",
+		info.Size, info.Method, info.Memorised, info.Capacity, info.Load)
+}
+
+// Info is Hopfield network metadata
+type Info struct {
+	// Size is the number of network neurons
+	Size int
+	// Memorised is the number of memorised patterns
+	Memorised int
+	// Capacity is the network capacity
+	Capacity int
+	// Method is the training method
+	Method string
+	// Load is the ratio of memorised patterns to the number of neurons
+	Load float64
+}
+
+// Info returns network metadata
+func (n Network) Info() Info {
+	size := n.weights.Symmetric()
+
+	return Info{
+		Size:      size,
+		Memorised: n.memorised,
+		Capacity:  n.Capacity(),
+		Method:    n.method,
+		Load:      float64(n.memorised) / float64(size),
+	}
+}
+
 // Store stores supplied patterns in network.
 // Store returns error if patterns is nil or if any of the patterns do not have the same dimension as number network neurons.
+// It returns error if the network training method is not supported.
+// If any of the patterns is empty, ErrEmptyPattern is returned.
 func (n *Network) Store(patterns []*Pattern) error {
+	if err := n.checkPatterns(patterns); err != nil {
+		return err
+	}
+	if err := n.store(patterns); err != nil {
+		return err
+	}
+	n.memorised += len(patterns)
+	if n.remember {
+		for _, p := range patterns {
+			n.remembered = append(n.remembered, p.clone())
+		}
+	}
+
+	return nil
+}
+
+// StoreUnique stores distinct supplied patterns in network and returns the number of actually stored patterns.
+// Patterns which duplicate any previously supplied pattern are skipped. If the network remembers the stored
+// patterns, patterns which duplicate any of the remembered patterns are skipped, too.
+// If invariant is true, the inverse of a pattern is considered to be its duplicate.
+// StoreUnique returns the same errors as Store.
+func (n *Network) StoreUnique(patterns []*Pattern, invariant bool) (int, error) {
+	if err := n.checkPatterns(patterns); err != nil {
+		return 0, err
+	}
+	// seen indexes the distinct patterns by their hash
+	seen := make(map[uint64][]*Pattern)
+	duplicate := func(p *Pattern) bool {
+		for _, s := range seen[p.Hash()] {
+			if s.equal(p) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range n.remembered {
+		seen[p.Hash()] = append(seen[p.Hash()], p)
+	}
+	var unique []*Pattern
+	for _, p := range patterns {
+		if duplicate(p) || (invariant && duplicate(Invert(p.clone()))) {
+			continue
+		}
+		seen[p.Hash()] = append(seen[p.Hash()], p)
+		unique = append(unique, p)
+	}
+	if len(unique) == 0 {
+		return 0, nil
+	}
+
+	return len(unique), n.Store(unique)
+}
+
+// StorePseudoInverse replaces network weights with the weights learnt from supplied patterns by regularized
+// pseudo-inverse (projection) learning rule: W = X(XᵀX + λI)⁻¹Xᵀ, where the columns of X are the patterns
+// and λ is lambda. Diagonal of the weights is set to zero. Unlike Hebbian learning, projection learning
+// stores correlated patterns, too; positive lambda stabilizes the inverse when the patterns are nearly
+// linearly dependent. Projection is not incremental, so the network memorises only the supplied patterns.
+// StorePseudoInverse returns error if lambda is negative, if the regularized overlap matrix of the patterns
+// can't be inverted or the same errors as Store.
+func (n *Network) StorePseudoInverse(patterns []*Pattern, lambda float64) error {
+	if err := n.checkPatterns(patterns); err != nil {
+		return err
+	}
+	// regularization can't be negative
+	if lambda < 0.0 || math.IsNaN(lambda) {
+		return fmt.Errorf("invalid regularization: %f", lambda)
+	}
+	dim, count := patterns[0].Len(), len(patterns)
+	// x stores patterns in its columns
+	x := mat.NewDense(dim, count, nil)
+	for j, p := range patterns {
+		x.SetCol(j, p.RawData())
+	}
+	// c is regularized overlap matrix of the patterns
+	c := mat.NewDense(count, count, nil)
+	c.Mul(x.T(), x)
+	for i := 0; i < count; i++ {
+		c.Set(i, i, c.At(i, i)+lambda)
+	}
+	var inv mat.Dense
+	if err := inv.Inverse(c); err != nil {
+		return fmt.Errorf("failed to invert patterns overlap matrix: %v", err)
+	}
+	var xinv, w mat.Dense
+	xinv.Mul(x, &inv)
+	w.Mul(&xinv, x.T())
+	for i := 0; i < dim; i++ {
+		for j := i + 1; j < dim; j++ {
+			n.weights.SetSym(i, j, w.At(i, j))
+		}
+		n.weights.SetSym(i, i, 0.0)
+	}
+	n.memorised = count
+	// projection weights do not keep mean activity of the patterns
+	n.activity = nil
+	if n.remember {
+		n.remembered = make([]*Pattern, count)
+		for i, p := range patterns {
+			n.remembered[i] = p.clone()
+		}
+	}
+
+	return nil
+}
+
+// StoreBatched stores supplied patterns in network in batches of batchSize patterns.
+// If progress is not nil, it is called with the number of patterns stored so far after each batch.
+// Hebbian learning is additive, so storing patterns in batches produces the same weights as storing them at once.
+// StoreBatched returns error if batchSize is non-positive; otherwise it returns the same errors as Store.
+// All patterns are validated before any of them is stored.
+func (n *Network) StoreBatched(patterns []*Pattern, batchSize int, progress func(done int)) error {
+	// batch must contain at least one pattern
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid batch size: %d", batchSize)
+	}
+	if err := n.checkPatterns(patterns); err != nil {
+		return err
+	}
+	for start := 0; start < len(patterns); start += batchSize {
+		end := start + batchSize
+		if end > len(patterns) {
+			end = len(patterns)
+		}
+		if err := n.Store(patterns[start:end]); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(end)
+		}
+	}
+
+	return nil
+}
+
+// checkPatterns returns error if patterns is nil or if any of the patterns is nil
+// or does not have the same dimension as number of network neurons
+func (n *Network) checkPatterns(patterns []*Pattern) error {
 	// patterns can't be nil
 	if len(patterns) == 0 {
 		return fmt.Errorf("invalid patterns supplied: %v", patterns)
@@ -84,31 +453,135 @@ func (n *Network) Store(patterns []*Pattern) error {
 		if p == nil {
 			return fmt.Errorf("invalid pattern supplied: %v", p)
 		}
+		// empty patterns are invalid
+		if p.Len() == 0 {
+			return ErrEmptyPattern
+		}
 		// incorrect dimension
 		if p.Len() != nCount {
 			return fmt.Errorf("invalid pattern dimension: %d", p.Len())
 		}
 	}
-	// store patterns in the network
-	switch n.method {
-	case "hebbian":
-		n.storeHebbian(patterns)
-	case "storkey":
-		n.storeStorkey(patterns)
+
+	return nil
+}
+
+// Retrain resets network weights and stores all the remembered patterns in the network using the new training method.
+// It returns error if unsupported training method is supplied or if the network does not remember the stored patterns.
+func (n *Network) Retrain(method string) error {
+	// if unsupported method is supplied we return error
+	if !supportedMethod(method) {
+		return fmt.Errorf("unsupported training method: %s", method)
+	}
+	// network must remember the stored patterns
+	if !n.remember {
+		return fmt.Errorf("network does not remember patterns")
 	}
+	n.method = strings.ToLower(method)
+
+	return n.relearn()
+}
+
+// StoreEvicting stores supplied pattern in network. If the network has already memorised as many patterns
+// as its capacity, the oldest remembered pattern is forgotten first, so the network keeps a sliding window
+// of the most recently stored patterns. Forgetting a pattern relearns the weights from the remaining patterns.
+// It returns error if invalid pattern is supplied or if the network does not remember the stored patterns.
+func (n *Network) StoreEvicting(p *Pattern) error {
+	if err := n.checkPatterns([]*Pattern{p}); err != nil {
+		return err
+	}
+	// network must remember the stored patterns
+	if !n.remember {
+		return fmt.Errorf("network does not remember patterns")
+	}
+	if n.memorised >= n.Capacity() && len(n.remembered) > 0 {
+		n.remembered = n.remembered[1:]
+		if err := n.relearn(); err != nil {
+			return err
+		}
+	}
+
+	return n.Store([]*Pattern{p})
+}
+
+// relearn resets network weights and stores all the remembered patterns in the network
+func (n *Network) relearn() error {
+	n.weights.Zero()
+	n.memorised = 0
+	n.activity = nil
+	if len(n.remembered) > 0 {
+		if err := n.store(n.remembered); err != nil {
+			return err
+		}
+	}
+	n.memorised = len(n.remembered)
 
 	return nil
 }
 
+// Nearest finds the remembered pattern which is the closest to the query pattern without running the network.
+// It returns the index of the closest remembered pattern in the order the patterns were stored and its Hamming distance
+// from the query pattern. If more patterns are equally close, the one which was stored first is returned.
+// It returns error if invalid pattern is supplied or if the network does not remember any patterns.
+func (n *Network) Nearest(query *Pattern) (int, int, error) {
+	// pattern can't be nil
+	if query == nil {
+		return -1, -1, fmt.Errorf("invalid pattern supplied: %v", query)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if query.Len() != nCount {
+		return -1, -1, fmt.Errorf("invalid pattern dimension: %v", query.Len())
+	}
+	// network must remember the stored patterns
+	if len(n.remembered) == 0 {
+		return -1, -1, fmt.Errorf("network does not remember patterns")
+	}
+	index, dist := -1, nCount+1
+	for i, p := range n.remembered {
+		if d := p.distance(query); d < dist {
+			index, dist = i, d
+		}
+	}
+
+	return index, dist, nil
+}
+
+// StoreAndCheck stores supplied pattern in network and returns the network energy of the stored pattern.
+// Low negative energy indicates the pattern is stored in a deep basin; high or positive energy warns of network overload.
+// StoreAndCheck returns the same errors as Store.
+func (n *Network) StoreAndCheck(p *Pattern) (float64, error) {
+	if err := n.Store([]*Pattern{p}); err != nil {
+		return 0.0, err
+	}
+
+	return n.energy(p), nil
+}
+
 // Restore tries to restore supplied pattern from network through mode restore process and returns it.
 // Mode can be either sync or async. If sync mode is requested, iters parameter is ignored.
 // If async mode is requested network runs for iters iterations and returns the restored pattern.
 // It returns error if invalid patterns is supplied, iters is negative or unsupported mode is supplied.
+// If the pattern is empty, ErrEmptyPattern is returned. If the network has not memorised any patterns,
+// error wrapping ErrUntrained is returned.
+//
+// Deprecated: Use RestoreRule instead. Sync mode corresponds to Synchronous rule run for a single iteration,
+// async mode corresponds to AsyncRandom rule.
 func (n *Network) Restore(p *Pattern, mode string, iters int) (*Pattern, error) {
+	return n.restore(p, mode, iters, n.rng)
+}
+
+// restore restores supplied pattern from network through mode restore process and returns it.
+// Async mode uses rng to generate the order of neuron updates. If rng is nil, default source is used.
+func (n *Network) restore(p *Pattern, mode string, iters int, rng *rand.Rand) (*Pattern, error) {
 	// pattern can't be nil
 	if p == nil {
 		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
 	}
+	// pattern can't be empty
+	if p.Len() == 0 {
+		return nil, ErrEmptyPattern
+	}
 	// pattern length must be the same as number of neurons
 	_, nCount := n.weights.Dims()
 	if p.Len() != nCount {
@@ -119,34 +592,618 @@ func (n *Network) Restore(p *Pattern, mode string, iters int) (*Pattern, error)
 		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
 	}
 	// only sync and async modes are allowed
+	var rule UpdateRule
 	switch mode {
 	case "sync":
-		return n.restoreSync(p)
+		rule, iters = Synchronous, 1
 	case "async":
-		return n.restoreAsync(p, iters)
+		rule = AsyncRandom
+	default:
+		return nil, fmt.Errorf("unsupported mode: %s", mode)
+	}
+	if !n.Trained() {
+		return nil, n.errUntrained()
+	}
+
+	return n.restoreRule(p, rule, iters, rng)
+}
+
+// errUntrained returns ErrUntrained annotated with the network training method
+func (n *Network) errUntrained() error {
+	return fmt.Errorf("%w: %s network has no memorised patterns", ErrUntrained, n.method)
+}
+
+// RestoreSync tries to restore supplied pattern from network through a single synchronous update and returns it.
+// If the local field of a neuron exactly equals its bias, the neuron keeps its state by default.
+// If rng is not nil, such neurons flip with 50% probability drawn from rng instead, which allows
+// studying how tie breaking affects the convergence. Tri-state neurons always rest on ties.
+// It returns error if invalid pattern is supplied.
+func (n *Network) RestoreSync(p *Pattern, rng *rand.Rand) (*Pattern, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	n.syncStep(p, rng)
+
+	return p, nil
+}
+
+// RestoreTimeout tries to restore supplied pattern from network through mode restore process and returns it.
+// Mode can be either sync or async. Unlike Restore, network runs until it either converges or timeout elapses.
+// If timeout elapses before the network converges, the lowest energy state found so far is returned.
+// If the network has divergence guard enabled and the network energy rises above the guard threshold, ErrDiverged is returned
+// along with the lowest energy state found before the divergence.
+// RestoreTimeout does not modify the supplied pattern and it always returns a new pattern.
+// It returns error if invalid pattern is supplied, timeout is non-positive or unsupported mode is supplied.
+func (n *Network) RestoreTimeout(p *Pattern, mode string, timeout time.Duration) (*Pattern, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// timeout must be a positive duration
+	if timeout <= 0 {
+		return nil, fmt.Errorf("invalid timeout: %s", timeout)
+	}
+	// only sync and async modes are allowed
+	var step func(*Pattern) bool
+	switch mode {
+	case "sync":
+		step = func(p *Pattern) bool { return n.syncStep(p, nil) }
+	case "async":
+		step = func(p *Pattern) bool { return n.asyncSweep(p, n.rng) }
+	default:
+		return nil, fmt.Errorf("unsupported mode: %s", mode)
+	}
+
+	deadline := time.Now().Add(timeout)
+	// supplied pattern is not modified
+	p = p.clone()
+	// best keeps the lowest energy state found so far
+	best, bestEnergy := p.clone(), n.energy(p)
+	// energies keeps energies of the sweeps watched by divergence guard
+	energies := []float64{bestEnergy}
+	for time.Now().Before(deadline) {
+		// network has converged if no neuron changed its state
+		if !step(p) {
+			return p, nil
+		}
+		energy := n.energy(p)
+		if energy < bestEnergy {
+			copy(best.RawData(), p.RawData())
+			bestEnergy = energy
+		}
+		if n.divWindow > 0 {
+			energies = append(energies, energy)
+			if len(energies) > n.divWindow {
+				energies = energies[1:]
+			}
+			if diverged(energies, n.divThreshold) {
+				return best, ErrDiverged
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// RestoreTrace tries to restore supplied pattern from network in async mode and returns it along with the network
+// energy change of each of the iters iterations. Async restore never increases the network energy, so all the
+// changes are non-positive. Positive change indicates a bug in the update rule.
+// It returns error if invalid pattern is supplied or iters is non-positive.
+func (n *Network) RestoreTrace(p *Pattern, iters int) (*Pattern, []float64, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	deltas := make([]float64, iters)
+	energy := n.energy(p)
+	for i := range deltas {
+		n.asyncSweep(p, n.rng)
+		newEnergy := n.energy(p)
+		deltas[i] = newEnergy - energy
+		energy = newEnergy
 	}
 
-	return nil, fmt.Errorf("unsupported mode: %s", mode)
+	return p, deltas, nil
+}
+
+// RestoreOverlapTrace tries to restore supplied pattern from network in async mode and returns it along with
+// the overlap of the network state with target pattern after each of the iters iterations. Overlap is the dot
+// product of the state and target divided by their dimension, so overlap 1 means the state equals target
+// and overlap -1 means it equals its inverse. RestoreOverlapTrace does not modify the target pattern.
+// It returns error if invalid pattern or target pattern is supplied or iters is non-positive.
+func (n *Network) RestoreOverlapTrace(p, target *Pattern, iters int) (*Pattern, []float64, error) {
+	_, nCount := n.weights.Dims()
+	for _, pattern := range []*Pattern{p, target} {
+		// pattern can't be nil
+		if pattern == nil {
+			return nil, nil, fmt.Errorf("invalid pattern supplied: %v", pattern)
+		}
+		// pattern length must be the same as number of neurons
+		if pattern.Len() != nCount {
+			return nil, nil, fmt.Errorf("invalid pattern dimension: %v", pattern.Len())
+		}
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	overlaps := make([]float64, iters)
+	for i := range overlaps {
+		n.asyncSweep(p, n.rng)
+		overlaps[i] = mat.Dot(p.Vec(), target.Vec()) / float64(nCount)
+	}
+
+	return p, overlaps, nil
+}
+
+// RestoreFlips tries to restore supplied pattern from network in async mode and returns it along with the total
+// number of neuron flips across all the iters iterations. Restoration stops early once a sweep flips no neuron.
+// Feeding back a stored pattern which is a fixed point of the network yields zero flips.
+// It returns error if invalid pattern is supplied or iters is non-positive.
+func (n *Network) RestoreFlips(p *Pattern, iters int) (*Pattern, int, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, 0, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, 0, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, 0, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	total := 0
+	for i := 0; i < iters; i++ {
+		flips := n.asyncSweepFlips(p, n.rng)
+		if flips == 0 {
+			break
+		}
+		total += flips
+	}
+
+	return p, total, nil
+}
+
+// RestoreHistory tries to restore supplied pattern from network in async mode and returns copies of the network
+// state sampled every everyNSweeps of the iters iterations. The final state is always returned as the last
+// of the states, even if iters is not a multiple of everyNSweeps.
+// It returns error if invalid pattern is supplied or either of iters or everyNSweeps is non-positive.
+func (n *Network) RestoreHistory(p *Pattern, iters int, everyNSweeps int) ([]*Pattern, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	// sampling interval must be a positive integer
+	if everyNSweeps <= 0 {
+		return nil, fmt.Errorf("invalid sampling interval: %d", everyNSweeps)
+	}
+	history := make([]*Pattern, 0, (iters+everyNSweeps-1)/everyNSweeps)
+	for i := 1; i <= iters; i++ {
+		n.asyncSweep(p, n.rng)
+		if i%everyNSweeps == 0 || i == iters {
+			history = append(history, p.clone())
+		}
+	}
+
+	return history, nil
+}
+
+// RestoreFieldOrder tries to restore supplied pattern from network and returns it.
+// Network runs for at most iters iterations updating neurons one by one in descending order of the magnitude
+// of their local fields, so the most confident neurons settle first. The order is recomputed in every iteration.
+// It returns error if invalid pattern is supplied or iters is non-positive.
+func (n *Network) RestoreFieldOrder(p *Pattern, iters int) (*Pattern, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	for iters > 0 && n.fieldOrderSweep(p) {
+		iters--
+	}
+
+	return p, nil
+}
+
+// RestorePrior tries to restore supplied input pattern from network in async mode using a prior pattern and returns it.
+// Network starts from the state given by the sign of alpha*input + (1-alpha)*prior, so alpha weighs how much
+// the input is trusted over the prior, and runs for iters iterations. If the blend is a tie, the input state is used.
+// RestorePrior modifies neither the input nor the prior pattern.
+// It returns error if invalid input or prior pattern is supplied, alpha is outside [0,1] or iters is non-positive.
+func (n *Network) RestorePrior(input, prior *Pattern, alpha float64, iters int) (*Pattern, error) {
+	_, nCount := n.weights.Dims()
+	for _, p := range []*Pattern{input, prior} {
+		// pattern can't be nil
+		if p == nil {
+			return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+		}
+		// pattern length must be the same as number of neurons
+		if p.Len() != nCount {
+			return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+		}
+	}
+	// alpha must be within [0,1]
+	if alpha < 0 || alpha > 1 || math.IsNaN(alpha) {
+		return nil, fmt.Errorf("invalid alpha: %f", alpha)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	// neuron states are split by the midpoint between on and off states
+	mid := (n.on + n.off) / 2
+	p := input.clone()
+	for i := 0; i < p.Len(); i++ {
+		blend := alpha*input.At(i) + (1-alpha)*prior.At(i)
+		switch {
+		case blend > mid:
+			p.RawData()[i] = n.on
+		case blend < mid:
+			p.RawData()[i] = n.off
+		}
+	}
+
+	return n.restoreAsync(p, iters, n.rng)
+}
+
+// ensembleNoise is the percentage of neurons flipped in the copies of the input restored by RestoreEnsemble.
+// At least one neuron is flipped in every copy, so the restores of small networks differ, too.
+const ensembleNoise = 5
+
+// RestoreEnsemble tries to restore supplied pattern from network by majority voting over restarts restores and returns it.
+// Each restore starts from a copy of the pattern with ensembleNoise percent, but at least one, of its neurons flipped at random and runs
+// in async mode for iters iterations. Each neuron is then set to the state it was restored to by the majority of restores;
+// tied votes keep the state of the supplied pattern. Voting corrects the restores stuck in spurious states.
+// All randomness is drawn from rng. If rng is nil, default source is used. RestoreEnsemble does not modify the supplied pattern.
+// It returns error if invalid pattern is supplied or either restarts or iters is non-positive.
+func (n *Network) RestoreEnsemble(p *Pattern, restarts, iters int, rng *rand.Rand) (*Pattern, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// we need at least one restart
+	if restarts <= 0 {
+		return nil, fmt.Errorf("invalid number of restarts: %d", restarts)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	// neuron states are split by the midpoint between on and off states
+	mid := (n.on + n.off) / 2
+	// votes accumulates the votes for the on state of each neuron
+	votes := make([]int, nCount)
+	flips := nCount * ensembleNoise / 100
+	if flips < 1 {
+		flips = 1
+	}
+	for r := 0; r < restarts; r++ {
+		input := p.clone()
+		for _, i := range perm(rng, nCount)[:flips] {
+			input.RawData()[i] = n.flip(input.At(i))
+		}
+		res, _ := n.restoreAsync(input, iters, rng)
+		for i := range votes {
+			switch {
+			case res.At(i) > mid:
+				votes[i]++
+			case res.At(i) < mid:
+				votes[i]--
+			}
+		}
+	}
+	res := p.clone()
+	for i, v := range votes {
+		switch {
+		case v > 0:
+			res.RawData()[i] = n.on
+		case v < 0:
+			res.RawData()[i] = n.off
+		}
+	}
+
+	return res, nil
+}
+
+// RestoreRegion tries to restore supplied pattern from network in async mode updating only the neurons in indices
+// and returns it. The rest of the neurons are held fixed in their supplied states, so they act as the context
+// of the restored region. Network runs for iters iterations, each updating the region neurons in a pseudorandom order.
+// It returns error if invalid pattern is supplied, if any of the indices is out of range or duplicate or if iters is non-positive.
+func (n *Network) RestoreRegion(p *Pattern, indices []int, iters int) (*Pattern, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// indices must be distinct neurons
+	seen := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= nCount {
+			return nil, fmt.Errorf("invalid neuron index: %d", i)
+		}
+		if seen[i] {
+			return nil, fmt.Errorf("duplicate neuron index: %d", i)
+		}
+		seen[i] = true
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	for ; iters > 0; iters-- {
+		for _, k := range perm(n.rng, len(indices)) {
+			i := indices[k]
+			p.RawData()[i] = n.activation(i, n.localField(i, p), p.At(i))
+		}
+	}
+
+	return p, nil
+}
+
+// DetectCycle runs synchronous updates of the network starting from the supplied pattern and returns the length
+// of the attractor cycle the network settles in: 1 for a fixed point, 2 for an oscillation between two states etc.
+// It returns 0 if no state repeats within maxSweeps updates. DetectCycle does not modify the supplied pattern.
+// It returns error if invalid pattern is supplied or maxSweeps is non-positive.
+func (n *Network) DetectCycle(p *Pattern, maxSweeps int) (int, error) {
+	// pattern can't be nil
+	if p == nil {
+		return 0, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return 0, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// number of max sweeps must be a positive integer
+	if maxSweeps <= 0 {
+		return 0, fmt.Errorf("invalid number of iterations: %d", maxSweeps)
+	}
+	state := p.clone()
+	// states keeps all visited states; seen indexes them by their hash
+	states := []*Pattern{state.clone()}
+	seen := map[uint64][]int{state.Hash(): {0}}
+	for sweep := 1; sweep <= maxSweeps; sweep++ {
+		n.syncStep(state, nil)
+		hash := state.Hash()
+		for _, i := range seen[hash] {
+			if states[i].equal(state) {
+				return sweep - i, nil
+			}
+		}
+		seen[hash] = append(seen[hash], len(states))
+		states = append(states, state.clone())
+	}
+
+	return 0, nil
+}
+
+// Stable checks if the supplied pattern is a fixed point of the network i.e. if none of the network neurons
+// would change its state when updated. Stable does not modify the supplied pattern.
+// It returns error if the supplied pattern is nil or if it does not have the same dimension as number of network neurons.
+func (n *Network) Stable(p *Pattern) (bool, error) {
+	// pattern can't be nil
+	if p == nil {
+		return false, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return false, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// h stores local fields of all neurons
+	h := n.localFields(p)
+	for i := 0; i < p.Len(); i++ {
+		if p.At(i) != n.activation(i, h.AtVec(i), p.At(i)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// StableCount returns the number of supplied patterns which are fixed points of the network.
+// Count lower than the number of stored patterns indicates network overload or highly correlated patterns.
+// It returns error if patterns is nil or if any of the patterns is invalid.
+func (n *Network) StableCount(patterns []*Pattern) (int, error) {
+	// patterns can't be nil
+	if len(patterns) == 0 {
+		return 0, fmt.Errorf("invalid patterns supplied: %v", patterns)
+	}
+	count := 0
+	for _, p := range patterns {
+		stable, err := n.Stable(p)
+		if err != nil {
+			return 0, err
+		}
+		if stable {
+			count++
+		}
+	}
+
+	return count, nil
 }
 
 // Energy calculates Hopfield network energy for a given pattern and returns it
+// Energy only reads the supplied pattern, so it never modifies it. Energy reads network weights without any
+// synchronization, so it must not be called concurrently with storing patterns; use ConcurrentNetwork for that.
 // It returns error if the supplied pattern is nil or if it does not have the same dimension as number of network neurons.
+// If the pattern is empty, ErrEmptyPattern is returned.
 func (n Network) Energy(p *Pattern) (float64, error) {
 	// pattern can't be nil
 	if p == nil {
 		return 0.0, fmt.Errorf("invalid pattern supplied: %v", p)
 	}
+	// pattern can't be empty
+	if p.Len() == 0 {
+		return 0.0, ErrEmptyPattern
+	}
 	// pattern length must be the same as number of neurons
 	_, nCount := n.weights.Dims()
 	// incorrect dimension
 	if p.Len() != nCount {
 		return 0.0, fmt.Errorf("invalid pattern dimension: %v", p.Len())
 	}
+
+	return n.energy(p), nil
+}
+
+// Energies calculates Hopfield network energy for each of the supplied patterns and returns them.
+// All the patterns are validated before any energy is calculated. The patterns are multiplied
+// by network weights at once, which is faster than calling Energy for each of them.
+// It returns error if patterns is nil or if any of the patterns is invalid.
+func (n *Network) Energies(patterns []*Pattern) ([]float64, error) {
+	if err := n.checkPatterns(patterns); err != nil {
+		return nil, err
+	}
+	dim, count := patterns[0].Len(), len(patterns)
+	// x stores patterns in its columns
+	x := mat.NewDense(dim, count, nil)
+	for j, p := range patterns {
+		x.SetCol(j, p.RawData())
+	}
+	var wx mat.Dense
+	wx.Mul(n.weights, x)
+	energies := make([]float64, count)
+	for j, p := range patterns {
+		energy := -0.5 * mat.Dot(p.Vec(), wx.ColView(j))
+		energy += mat.Dot(n.bias, p.Vec())
+		energy -= mat.Dot(n.external, p.Vec())
+		energies[j] = energy
+	}
+
+	return energies, nil
+}
+
+// EnergyGap computes the minimum network energy increase over all the patterns which differ from pattern p
+// in a single neuron and returns it. Positive gap confirms p is a strict local minimum of the network energy
+// and its magnitude measures the depth of its basin; negative gap means flipping some neuron lowers the energy.
+// It returns error if the supplied pattern is nil or if it does not have the same dimension as number of network neurons.
+func (n *Network) EnergyGap(p *Pattern) (float64, error) {
+	// pattern can't be nil
+	if p == nil {
+		return 0.0, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return 0.0, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// h stores local fields of all neurons
+	h := n.localFields(p)
+	gap := math.Inf(1)
+	for i := 0; i < p.Len(); i++ {
+		gap = math.Min(gap, n.flipEnergy(p, i, h.AtVec(i)))
+	}
+
+	return gap, nil
+}
+
+// NeuronInfluence computes mean absolute energy contribution of every network neuron over the supplied patterns
+// and returns them. Network energy is the sum of the contributions of its neurons: contribution of i-th neuron
+// is -0.5·pᵢ·Σⱼ wᵢⱼpⱼ + bᵢpᵢ - hᵢpᵢ, where h is the external field. Neurons with high influence shape
+// the energy landscape the most, so they are the candidates to keep when the network is pruned.
+// It returns error if patterns is nil or if any of the patterns is invalid.
+func (n *Network) NeuronInfluence(patterns []*Pattern) ([]float64, error) {
+	if err := n.checkPatterns(patterns); err != nil {
+		return nil, err
+	}
+	dim := patterns[0].Len()
+	influence := make([]float64, dim)
+	wp := mat.NewVecDense(dim, nil)
+	for _, p := range patterns {
+		wp.MulVec(n.weights, p.Vec())
+		for i := range influence {
+			e := -0.5*p.At(i)*wp.AtVec(i) + (n.bias.AtVec(i)-n.external.AtVec(i))*p.At(i)
+			influence[i] += math.Abs(e) / float64(len(patterns))
+		}
+	}
+
+	return influence, nil
+}
+
+// flipEnergy returns the change of network energy caused by flipping i-th neuron of pattern p
+// whose local field is h. Diagonal weights are zero, so the change only depends on the local field.
+func (n *Network) flipEnergy(p *Pattern, i int, h float64) float64 {
+	delta := n.flip(p.At(i)) - p.At(i)
+
+	return -delta * (h - n.bias.AtVec(i))
+}
+
+// energy calculates Hopfield network energy for a given pattern without validating it
+func (n Network) energy(p *Pattern) float64 {
 	// hopfield energy
 	energy := -0.5 * mat.Inner(p.Vec(), n.weights, p.Vec())
 	energy += mat.Dot(n.bias, p.Vec())
+	energy -= mat.Dot(n.external, p.Vec())
 
-	return energy, nil
+	return energy
+}
+
+// store stores patterns in the network using the network training method.
+// It returns error if the network training method is not supported.
+func (n *Network) store(patterns []*Pattern) error {
+	switch n.method {
+	case "hebbian":
+		n.storeHebbian(patterns)
+	case "storkey":
+		n.storeStorkey(patterns)
+	default:
+		fn, ok := learningRule(n.method)
+		if !ok {
+			return fmt.Errorf("unsupported training method: %s", n.method)
+		}
+		fn(n.weights, patterns)
+	}
+
+	return nil
 }
 
 // hebbian uses Hebbian learning to generate weights matrix
@@ -154,7 +1211,7 @@ func (n *Network) storeHebbian(patterns []*Pattern) {
 	// pattern dimension [same as nr. of neurons]
 	dim := patterns[0].Len()
 	// w stores partial weights for each pattern
-	w := mat.NewSymDense(dim, nil)
+	w := n.scratchWeights(dim)
 	// we only traverse higher triangular matrix because we are using Symmetric matrix
 	for i := 0; i < dim; i++ {
 		for j := i + 1; j < dim; j++ {
@@ -163,87 +1220,270 @@ func (n *Network) storeHebbian(patterns []*Pattern) {
 			}
 		}
 	}
+	if n.meanSubtract {
+		n.subtractMean(w, patterns)
+	}
+	// normalized weights are the mean of the weights of all stored patterns
+	if n.normalize {
+		count := float64(n.memorised + len(patterns))
+		n.weights.ScaleSym(float64(n.memorised)/count, n.weights)
+		w.ScaleSym(1/count, w)
+	}
 	// Add nwe weights matrix to network weights matrix
 	n.weights.AddSym(n.weights, w)
 }
 
-// storkey uses Storkey learning to generate weights matrix
-func (n *Network) storeStorkey(patterns []*Pattern) {
-	// pattern dimension [same as nr. of neurons]
+// subtractMean adjusts Hebbian weights w of the patterns stored in the network by covariance rule.
+// Covariance weights of all stored patterns are Σ(p-m)(p-m)ᵀ/dim = Σppᵀ/dim - ssᵀ/(count*dim), where m is
+// the mean and s is the sum of the stored patterns, so w is adjusted by the change of the subtracted term.
+func (n *Network) subtractMean(w *mat.SymDense, patterns []*Pattern) {
 	dim := patterns[0].Len()
-	// weights matrix
-	w := mat.NewSymDense(dim, nil)
-	// we only traverse higher triangular matrix because we are using Symmetric matrix
-	var sum float64
+	if n.activity == nil {
+		n.activity = make([]float64, dim)
+	}
+	// old is the sum of the patterns stored before
+	old := make([]float64, dim)
+	copy(old, n.activity)
+	for _, p := range patterns {
+		for i := range n.activity {
+			n.activity[i] += p.At(i)
+		}
+	}
+	prev, count := float64(n.memorised), float64(n.memorised+len(patterns))
 	for i := 0; i < dim; i++ {
 		for j := i + 1; j < dim; j++ {
-			for _, p := range patterns {
-				sum = p.At(i) * p.At(j)
-				sum -= p.At(i) * localField(w, p, j, i)
-				sum -= p.At(j) * localField(w, p, i, j)
-				sum *= 1 / float64(dim)
-				w.SetSym(i, j, w.At(i, j)+sum)
+			delta := -n.activity[i] * n.activity[j] / count
+			if prev > 0 {
+				delta += old[i] * old[j] / prev
 			}
+			w.SetSym(i, j, w.At(i, j)+delta/float64(dim))
 		}
 	}
+}
+
+// storkey uses Storkey learning to generate weights matrix
+func (n *Network) storeStorkey(patterns []*Pattern) {
+	// pattern dimension [same as nr. of neurons]
+	dim := patterns[0].Len()
+	// weights matrix
+	w := n.scratchWeights(dim)
+	storkey(w, n.scratchFields(dim), patterns)
 	// Add nwe weights matrix to network weights matrix
 	n.weights.AddSym(n.weights, w)
 }
 
-// localField calculates Storkey local field for a given pattern and returns it
-func localField(w *mat.SymDense, p *Pattern, i, j int) float64 {
-	sum := 0.0
-	// calculate sum for all but i and j neuron weights
-	for k := 0; k < p.Len(); k++ {
-		if k != i && k != j {
-			// TODO: Raw access turns out to be slowe than library access WTF?!
-			//sum += w.RawSymmetric().Data[i*w.RawSymmetric().Stride+k] * p.At(k)
-			sum += w.At(i, k) * p.At(k)
-		}
+// scratchWeights returns zeroed dim x dim scratch weights matrix. The matrix is allocated once and reused
+// across stores, so it must not be retained between them.
+func (n *Network) scratchWeights(dim int) *mat.SymDense {
+	if n.wBuf == nil {
+		n.wBuf = mat.NewSymDense(dim, nil)
 	}
+	n.wBuf.Zero()
 
-	return sum
+	return n.wBuf
 }
 
-// restoreSync restores patterns from the network synchronously
-func (n *Network) restoreSync(p *Pattern) (*Pattern, error) {
-	p.Vec().MulVec(n.weights, p.Vec())
-	for i := 0; i < p.Len(); i++ {
-		if p.At(i) >= n.bias.At(i, 0) {
-			p.RawData()[i] = 1.0
-		} else {
-			p.RawData()[i] = -1.0
-		}
+// scratchFields returns zeroed scratch local fields vector of dim length. The vector is allocated once
+// and reused across stores, so it must not be retained between them.
+func (n *Network) scratchFields(dim int) *mat.VecDense {
+	if n.hBuf == nil {
+		n.hBuf = mat.NewVecDense(dim, nil)
 	}
+	n.hBuf.Zero()
 
-	return p, nil
+	return n.hBuf
 }
 
-// restoreAsync restores patterns from the network synchronously
-func (n *Network) restoreAsync(p *Pattern, iters int) (*Pattern, error) {
-	// we will bound the number of iterations to eqiters and maxiters
-	var nState float64
+// restoreAsync restores patterns from the network asynchronously
+func (n *Network) restoreAsync(p *Pattern, iters int, rng *rand.Rand) (*Pattern, error) {
 	for iters > 0 {
-		// generate pseudorandom sequence
-		seq := rand.Perm(p.Len())
-		for _, i := range seq {
-			sum := 0.0
-			for j := 0; j < p.Len(); j++ {
-				// some all connections to j-th neuron
-				sum += n.weights.At(i, j) * p.At(j)
-			}
-			// if the sum is bigger than bias
-			if sum >= n.bias.At(i, 0) {
-				nState = 1.0
-			} else {
-				nState = -1.0
-			}
-			if p.At(i)*nState < 0.0 {
-				p.RawData()[i] = nState
-			}
-		}
+		n.asyncSweep(p, rng)
 		iters--
 	}
 
 	return p, nil
 }
+
+// syncStep updates all network neurons at once and reports whether any neuron changed its state.
+// If rng is not nil, neurons whose local field exactly equals their bias flip with 50% probability drawn from rng.
+func (n *Network) syncStep(p *Pattern, rng *rand.Rand) bool {
+	// h stores local fields of all neurons
+	h := n.localFields(p)
+	changed := false
+	for i := 0; i < p.Len(); i++ {
+		nState := n.activation(i, h.AtVec(i), p.At(i))
+		// tri-state neurons rest on ties
+		if rng != nil && !n.triState && h.AtVec(i) == n.bias.AtVec(i) && rng.Float64() < 0.5 {
+			nState = n.flip(nState)
+		}
+		if p.At(i) != nState {
+			p.RawData()[i] = nState
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// asyncSweep updates all network neurons one by one in a pseudorandom order generated by rng
+// and reports whether any neuron changed its state. If rng is nil, default source is used.
+func (n *Network) asyncSweep(p *Pattern, rng *rand.Rand) bool {
+	return n.asyncSweepFlips(p, rng) > 0
+}
+
+// asyncSweepFlips updates all network neurons one by one in a pseudorandom order generated by rng
+// and returns the number of neurons which changed their state. If rng is nil, default source is used.
+func (n *Network) asyncSweepFlips(p *Pattern, rng *rand.Rand) int {
+	flips := 0
+	// generate pseudorandom sequence
+	seq := perm(rng, p.Len())
+	for _, i := range seq {
+		nState := n.activation(i, n.localField(i, p), p.At(i))
+		if p.At(i) != nState {
+			p.RawData()[i] = nState
+			flips++
+		}
+	}
+
+	return flips
+}
+
+// fieldOrderSweep updates all network neurons one by one in descending order of the magnitude
+// of their local fields and reports whether any neuron changed its state
+func (n *Network) fieldOrderSweep(p *Pattern) bool {
+	// h stores local fields of all neurons
+	h := n.localFields(p)
+	seq := make([]int, p.Len())
+	for i := range seq {
+		seq[i] = i
+	}
+	sort.SliceStable(seq, func(a, b int) bool {
+		return math.Abs(h.AtVec(seq[a])-n.bias.At(seq[a], 0)) > math.Abs(h.AtVec(seq[b])-n.bias.At(seq[b], 0))
+	})
+	changed := false
+	for _, i := range seq {
+		nState := n.activation(i, n.localField(i, p), p.At(i))
+		if p.At(i) != nState {
+			p.RawData()[i] = nState
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// flip returns the opposite of neuron state. States other than on and off are returned unchanged.
+func (n *Network) flip(state float64) float64 {
+	switch state {
+	case n.on:
+		return n.off
+	case n.off:
+		return n.on
+	}
+
+	return state
+}
+
+// localFields computes local fields of all network neurons for pattern p and returns them
+func (n *Network) localFields(p *Pattern) *mat.VecDense {
+	h := mat.NewVecDense(p.Len(), nil)
+	h.MulVec(n.weights, p.Vec())
+	h.AddVec(h, n.external)
+
+	return h
+}
+
+// localField computes local field of i-th network neuron for pattern p and returns it
+func (n *Network) localField(i int, p *Pattern) float64 {
+	if n.rawAccess {
+		return n.localFieldRaw(i, p)
+	}
+	// sum all connections to i-th neuron
+	sum := n.external.AtVec(i)
+	for j := 0; j < p.Len(); j++ {
+		sum += n.weights.At(i, j) * p.At(j)
+	}
+
+	return sum
+}
+
+// localFieldRaw computes local field of i-th network neuron for pattern p indexing raw weights data and returns it.
+// Symmetric weights store only their upper triangle, so the weights of the i-th row left of the diagonal
+// are read from the i-th column.
+func (n *Network) localFieldRaw(i int, p *Pattern) float64 {
+	raw := n.weights.RawSymmetric()
+	data := p.RawData()
+	// sum all connections to i-th neuron
+	sum := n.external.AtVec(i)
+	for j := 0; j < i; j++ {
+		sum += raw.Data[j*raw.Stride+i] * data[j]
+	}
+	row := raw.Data[i*raw.Stride : i*raw.Stride+raw.N]
+	for j := i; j < len(data); j++ {
+		sum += row[j] * data[j]
+	}
+
+	return sum
+}
+
+// activation returns the state of i-th neuron in state cur for the local field h.
+// If the local field exactly equals the bias, flipping the neuron would not lower the network energy,
+// so the neuron keeps its current state unless it is in neither of the on and off states.
+func (n *Network) activation(i int, h, cur float64) float64 {
+	// tri-state neurons rest if the local field is within dead band around bias
+	if n.triState && math.Abs(h-n.bias.At(i, 0)) <= n.deadBand {
+		return 0.0
+	}
+	// exact tie keeps the current state
+	if h == n.bias.At(i, 0) && (cur == n.on || cur == n.off) {
+		return cur
+	}
+	// if the local field is bigger than bias
+	if h >= n.bias.At(i, 0) {
+		return n.on
+	}
+
+	return n.off
+}
+
+// addNoise flips exactly pcnt percent of distinct neurons of pattern p chosen at random by rng and returns it.
+// If rng is nil, default source is used. addNoise modifies the pattern p in place.
+func (n *Network) addNoise(p *Pattern, pcnt int, rng *rand.Rand) *Pattern {
+	for _, i := range perm(rng, p.Len())[:pcnt*p.Len()/100] {
+		p.RawData()[i] = n.flip(p.At(i))
+	}
+
+	return p
+}
+
+// perm returns pseudorandom permutation of integers [0,n) generated by rng.
+// If rng is nil, default source is used.
+func perm(rng *rand.Rand, n int) []int {
+	if rng == nil {
+		return rand.Perm(n)
+	}
+
+	return rng.Perm(n)
+}
+
+// intn returns pseudorandom integer in [0,n) generated by rng.
+// If rng is nil, default source is used.
+func intn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+
+	return rng.Intn(n)
+}
+
+// diverged reports whether the last of energies rose by more than threshold above their minimum
+func diverged(energies []float64, threshold float64) bool {
+	last := energies[len(energies)-1]
+	for _, e := range energies {
+		if last-e > threshold {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,227 @@
+package hopfield
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Network32 is Hopfield network which stores its weights in single precision.
+// Network32 only keeps the upper triangle of its symmetric weights matrix with zero diagonal in float32 values,
+// i.e. 4 bytes per each of N(N-1)/2 weights, whereas the symmetric weights matrix of Network is backed by N² float64
+// values, i.e. 8 bytes per each of N² weights. Network32 weights thus take about a quarter of the memory of Network
+// weights: 10,000 neurons take ~200MB instead of 800MB.
+// The price is precision: weights are rounded to float32 (~7 significant digits) whenever they are updated,
+// so rounding errors accumulate as more patterns are stored, which slightly lowers recall of heavily loaded networks.
+// Local fields and energies are accumulated in float64.
+//
+// Network32 is a minimal network and it does not support most of the features of Network:
+//   - neurons have zero bias and no external field, and their states are always +1 and -1
+//   - only the built-in hebbian and storkey methods are supported, learning rules registered
+//     by RegisterLearningRule are rejected
+//   - it can't be configured by options and it can't be saved or loaded
+//   - patterns are only restored in sync or async mode and async restores draw randomness from default source
+type Network32 struct {
+	// size is the number of network neurons
+	size int
+	// weights are upper triangle network neurons weights without diagonal stored row by row
+	weights []float32
+	// method is training method
+	method string
+	// memorised keeps a count of memorized patterns
+	memorised int
+}
+
+// NewNetwork32 creates new single precision Hopfield network which is trained using the training method and returns it.
+// NewNetwork32 returns error if either non-positive size is supplied or unsupported training method is supplied.
+func NewNetwork32(size int, method string) (*Network32, error) {
+	// can't have negative number of weights
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid network size: %d", size)
+	}
+	// if unsupported method is supplied we return error
+	if !strings.EqualFold("hebbian", method) && !strings.EqualFold("storkey", method) {
+		return nil, fmt.Errorf("unsupported training method: %s", method)
+	}
+
+	return &Network32{
+		size:    size,
+		weights: make([]float32, size*(size-1)/2),
+		method:  strings.ToLower(method),
+	}, nil
+}
+
+// Weight returns the weight of the connection between i-th and j-th neuron
+func (n *Network32) Weight(i, j int) float64 {
+	if i == j {
+		return 0.0
+	}
+
+	return float64(n.weights[n.index(i, j)])
+}
+
+// Capacity returns network capacity
+func (n *Network32) Capacity() int {
+	return int(math.Floor(capacity(n.size, n.method)))
+}
+
+// Memorised returns count of memorised patterns
+func (n *Network32) Memorised() int {
+	return n.memorised
+}
+
+// Store stores supplied patterns in network.
+// Store returns error if patterns is nil or if any of the patterns do not have the same dimension as number network neurons.
+func (n *Network32) Store(patterns []*Pattern) error {
+	// patterns can't be nil
+	if len(patterns) == 0 {
+		return fmt.Errorf("invalid patterns supplied: %v", patterns)
+	}
+	for _, p := range patterns {
+		if err := n.checkPattern(p); err != nil {
+			return err
+		}
+	}
+	switch n.method {
+	case "hebbian":
+		n.storeHebbian(patterns)
+	case "storkey":
+		n.storeStorkey(patterns)
+	}
+	n.memorised += len(patterns)
+
+	return nil
+}
+
+// Restore tries to restore supplied pattern from network through mode restore process and returns it.
+// Mode can be either sync or async. If sync mode is requested, iters parameter is ignored.
+// If async mode is requested network runs for iters iterations and returns the restored pattern.
+// It returns error if invalid patterns is supplied, iters is negative or unsupported mode is supplied.
+func (n *Network32) Restore(p *Pattern, mode string, iters int) (*Pattern, error) {
+	if err := n.checkPattern(p); err != nil {
+		return nil, err
+	}
+	// number of max iterations must be a positive integer
+	if strings.EqualFold("async", mode) && iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	// only sync and async modes are allowed
+	switch mode {
+	case "sync":
+		h := make([]float64, n.size)
+		for i := range h {
+			h[i] = n.localField(i, p)
+		}
+		for i, hi := range h {
+			p.RawData()[i] = activation32(hi, p.At(i))
+		}
+		return p, nil
+	case "async":
+		for ; iters > 0; iters-- {
+			for _, i := range perm(nil, n.size) {
+				p.RawData()[i] = activation32(n.localField(i, p), p.At(i))
+			}
+		}
+		return p, nil
+	}
+
+	return nil, fmt.Errorf("unsupported mode: %s", mode)
+}
+
+// Energy calculates Hopfield network energy for a given pattern and returns it
+// It returns error if the supplied pattern is nil or if it does not have the same dimension as number of network neurons.
+func (n *Network32) Energy(p *Pattern) (float64, error) {
+	if err := n.checkPattern(p); err != nil {
+		return 0.0, err
+	}
+	// diagonal is zero, so the energy only sums the upper triangle
+	energy := 0.0
+	for i := 0; i < n.size; i++ {
+		row := n.weights[n.index(i, i+1):]
+		for j := i + 1; j < n.size; j++ {
+			energy -= float64(row[j-i-1]) * p.At(i) * p.At(j)
+		}
+	}
+
+	return energy, nil
+}
+
+// checkPattern returns error if pattern p is nil or does not have the same dimension as number of network neurons
+func (n *Network32) checkPattern(p *Pattern) error {
+	// pattern can't be nil
+	if p == nil {
+		return fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern length must be the same as number of neurons
+	if p.Len() != n.size {
+		return fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+
+	return nil
+}
+
+// storeHebbian uses Hebbian learning to update weights
+func (n *Network32) storeHebbian(patterns []*Pattern) {
+	dim := float64(n.size)
+	for i := 0; i < n.size; i++ {
+		for j := i + 1; j < n.size; j++ {
+			sum := 0.0
+			for _, p := range patterns {
+				sum += p.At(i) * p.At(j) / dim
+			}
+			k := n.index(i, j)
+			n.weights[k] = float32(float64(n.weights[k]) + sum)
+		}
+	}
+}
+
+// storeStorkey uses Storkey learning to update weights
+func (n *Network32) storeStorkey(patterns []*Pattern) {
+	// new weights are computed in double precision before they are added to network weights
+	w := mat.NewSymDense(n.size, nil)
+	storkey(w, mat.NewVecDense(n.size, nil), patterns)
+	for i := 0; i < n.size; i++ {
+		for j := i + 1; j < n.size; j++ {
+			k := n.index(i, j)
+			n.weights[k] = float32(float64(n.weights[k]) + w.At(i, j))
+		}
+	}
+}
+
+// localField computes local field of i-th network neuron for pattern p and returns it
+func (n *Network32) localField(i int, p *Pattern) float64 {
+	sum := 0.0
+	for j := 0; j < n.size; j++ {
+		if j != i {
+			sum += float64(n.weights[n.index(i, j)]) * p.At(j)
+		}
+	}
+
+	return sum
+}
+
+// index returns index of the weight of the connection between i-th and j-th neuron, i != j, in weights slice
+func (n *Network32) index(i, j int) int {
+	if i > j {
+		i, j = j, i
+	}
+	// rows before i-th row have size-1, size-2, ..., size-i weights
+	return i*(2*n.size-i-1)/2 + j - i - 1
+}
+
+// activation32 returns the state of bipolar neuron in state cur for the local field h.
+// Exact tie keeps the current state of the neuron.
+func activation32(h, cur float64) float64 {
+	switch {
+	case h > 0.0:
+		return 1.0
+	case h < 0.0:
+		return -1.0
+	case cur == 1.0 || cur == -1.0:
+		return cur
+	}
+
+	return 1.0
+}
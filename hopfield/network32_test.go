@@ -0,0 +1,109 @@
+package hopfield
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNetwork32(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := NewNetwork32(5, "Storkey")
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.Len(n.weights, 10)
+	assert.Equal(0, n.Memorised())
+
+	size := -2
+	errString := "invalid network size: %d"
+	n, err = NewNetwork32(size, "hebbian")
+	assert.Nil(n)
+	assert.EqualError(err, fmt.Sprintf(errString, size))
+
+	method := "foobar"
+	errString = "unsupported training method: %s"
+	n, err = NewNetwork32(5, method)
+	assert.Nil(n)
+	assert.EqualError(err, fmt.Sprintf(errString, method))
+}
+
+func TestNetwork32(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	rng := rand.New(rand.NewSource(1))
+	// odd number of patterns avoids exact zero local fields which single precision may round either way
+	patterns := randomPatterns(9, size, rng)
+
+	for _, method := range []string{"hebbian", "storkey"} {
+		n32, err := NewNetwork32(size, method)
+		assert.NotNil(n32)
+		assert.NoError(err)
+		n64, err := NewNetwork(size, method)
+		assert.NotNil(n64)
+		assert.NoError(err)
+
+		var invalid []*Pattern
+		errString := "invalid patterns supplied: %v"
+		err = n32.Store(invalid)
+		assert.EqualError(err, fmt.Sprintf(errString, invalid))
+
+		pattern := Encode([]float64{1.0, -1.0})
+		errString = "invalid pattern dimension: %v"
+		err = n32.Store([]*Pattern{pattern})
+		assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+		res, err := n32.Restore(pattern, "async", 10)
+		assert.Nil(res)
+		assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+		mode := "foobar"
+		errString = "unsupported mode: %s"
+		res, err = n32.Restore(patterns[0].clone(), mode, 10)
+		assert.Nil(res)
+		assert.EqualError(err, fmt.Sprintf(errString, mode))
+
+		err = n32.Store(patterns)
+		assert.NoError(err)
+		err = n64.Store(patterns)
+		assert.NoError(err)
+		assert.Equal(len(patterns), n32.Memorised())
+		assert.Equal(n64.Capacity(), n32.Capacity())
+
+		// weights differ by single precision rounding only
+		for i := 0; i < size; i++ {
+			for j := 0; j < size; j++ {
+				assert.InDelta(n64.weights.At(i, j), n32.Weight(i, j), 1e-6)
+			}
+		}
+
+		for _, p := range patterns {
+			e32, err := n32.Energy(p)
+			assert.NoError(err)
+			e64, err := n64.Energy(p)
+			assert.NoError(err)
+			assert.InDelta(e64, e32, 1e-4)
+		}
+
+		// both networks recall the same patterns from the same noisy inputs
+		for _, mode := range []string{"sync", "async"} {
+			recalled32, recalled64 := 0, 0
+			for _, p := range patterns {
+				noisy := n64.addNoise(p.clone(), 10, rng)
+				res32, err := n32.Restore(noisy.clone(), mode, 10)
+				assert.NoError(err)
+				if res32.equal(p) {
+					recalled32++
+				}
+				res64, err := n64.Restore(noisy.clone(), mode, 10)
+				assert.NoError(err)
+				if res64.equal(p) {
+					recalled64++
+				}
+			}
+			assert.Equal(recalled64, recalled32, method+" "+mode)
+		}
+	}
+}
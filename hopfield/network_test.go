@@ -1,8 +1,12 @@
 package hopfield
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gonum.org/v1/gonum/mat"
@@ -29,6 +33,37 @@ func TestNewNetwork(t *testing.T) {
 	n, err = NewNetwork(size, method)
 	assert.Nil(n)
 	assert.EqualError(err, fmt.Sprintf(errString, method))
+
+	errString = "invalid dead band: %f"
+	method = "hebbian"
+	deadBand := -0.1
+	n, err = NewNetwork(size, method, WithTriState(deadBand))
+	assert.Nil(n)
+	assert.EqualError(err, fmt.Sprintf(errString, deadBand))
+
+	errString = "invalid neuron states: %f, %f"
+	on, off := -1.0, 1.0
+	n, err = NewNetwork(size, method, WithStates(on, off))
+	assert.Nil(n)
+	assert.EqualError(err, fmt.Sprintf(errString, on, off))
+
+	errString = "invalid divergence guard: %d, %f"
+	window, threshold := -1, 0.5
+	n, err = NewNetwork(size, method, WithDivergenceGuard(window, threshold))
+	assert.Nil(n)
+	assert.EqualError(err, fmt.Sprintf(errString, window, threshold))
+
+	errString = "network size %d exceeds maximum size %d"
+	maxSize := 1000
+	size = maxSize + 1
+	n, err = NewNetwork(size, method, WithMaxSize(maxSize))
+	assert.Nil(n)
+	assert.EqualError(err, fmt.Sprintf(errString, size, maxSize))
+
+	size = maxSize
+	n, err = NewNetwork(size, method, WithMaxSize(maxSize))
+	assert.NotNil(n)
+	assert.NoError(err)
 }
 
 func TestWeights(t *testing.T) {
@@ -46,6 +81,24 @@ func TestWeights(t *testing.T) {
 	assert.Equal(cols, size)
 }
 
+func TestRawWeights(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 5
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	n.weights.SetSym(3, 1, 0.5)
+	data, stride := n.RawWeights()
+	assert.Equal(size, stride)
+	assert.Equal(0.5, data[1*stride+3])
+
+	// data is live
+	data[2*stride+4] = -0.25
+	assert.Equal(-0.25, n.Weights().At(4, 2))
+}
+
 func TestBias(t *testing.T) {
 	assert := assert.New(t)
 
@@ -61,6 +114,76 @@ func TestBias(t *testing.T) {
 	assert.Equal(1, cols)
 }
 
+func TestBiasSlice(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 5
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	n.bias.SetVec(2, 0.5)
+	bias := n.BiasSlice()
+	assert.Equal([]float64{0.0, 0.0, 0.5, 0.0, 0.0}, bias)
+
+	// modifying the copy does not affect the network
+	bias[0] = 1.0
+	bias[2] = -1.0
+	assert.Equal(0.0, n.Bias().At(0, 0))
+	assert.Equal(0.5, n.Bias().At(2, 0))
+}
+
+func TestSetExternalField(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 10
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	patterns := randomPatterns(2, size, rand.New(rand.NewSource(1)))
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	field := []float64{1.0, -1.0}
+	errString := "invalid external field dimension: %d"
+	err = n.SetExternalField(field)
+	assert.EqualError(err, fmt.Sprintf(errString, len(field)))
+
+	// stored pattern is stable without external field
+	stable, err := n.Stable(patterns[0])
+	assert.NoError(err)
+	assert.True(stable)
+	energy, err := n.Energy(patterns[0])
+	assert.NoError(err)
+
+	// strong external field forces the first neuron to the opposite sign
+	field = make([]float64, size)
+	field[0] = -10.0 * patterns[0].At(0)
+	err = n.SetExternalField(field)
+	assert.NoError(err)
+
+	extEnergy, err := n.Energy(patterns[0])
+	assert.NoError(err)
+	assert.InDelta(energy+10.0, extEnergy, 1e-9)
+
+	stable, err = n.Stable(patterns[0])
+	assert.NoError(err)
+	assert.False(stable)
+	for _, mode := range []string{"sync", "async"} {
+		res, err := n.Restore(patterns[0].clone(), mode, 5)
+		assert.NoError(err)
+		assert.Equal(-patterns[0].At(0), res.At(0))
+	}
+
+	// zero external field restores the original dynamics
+	err = n.SetExternalField(make([]float64, size))
+	assert.NoError(err)
+	stable, err = n.Stable(patterns[0])
+	assert.NoError(err)
+	assert.True(stable)
+}
+
 func TestCapacity(t *testing.T) {
 	assert := assert.New(t)
 
@@ -80,6 +203,32 @@ func TestCapacity(t *testing.T) {
 	assert.True(capStorkey > capHebbian)
 }
 
+func TestEffectiveCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	assert.Equal(0.0, EffectiveCapacity(nil, "hebbian"))
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	// random patterns are nearly uncorrelated
+	random := randomPatterns(5, size, rand.New(rand.NewSource(1)))
+	capRandom := EffectiveCapacity(random, "hebbian")
+	assert.True(capRandom <= float64(n.Capacity()+1))
+	assert.True(capRandom > 0.8*float64(n.Capacity()))
+
+	// correlated patterns differ from each other in a few neurons only
+	correlated := make([]*Pattern, 5)
+	for i := range correlated {
+		correlated[i] = random[0].clone()
+		correlated[i].RawData()[i] = -correlated[i].RawData()[i]
+	}
+	capCorrelated := EffectiveCapacity(correlated, "hebbian")
+	assert.True(capCorrelated < 0.1*capRandom)
+}
+
 func TestMemorised(t *testing.T) {
 	assert := assert.New(t)
 
@@ -87,6 +236,310 @@ func TestMemorised(t *testing.T) {
 	assert.NotNil(n)
 	assert.NoError(err)
 	assert.True(n.Memorised() == 0)
+
+	err = n.Store(randomPatterns(3, 5, rand.New(rand.NewSource(1))))
+	assert.NoError(err)
+	assert.Equal(3, n.Memorised())
+}
+
+func TestRetrain(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 10
+	patterns := randomPatterns(3, size, rand.New(rand.NewSource(1)))
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	errString := "network does not remember patterns"
+	err = n.Retrain("storkey")
+	assert.EqualError(err, errString)
+
+	n, err = NewNetwork(size, "hebbian", WithRemember())
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store(patterns[:2])
+	assert.NoError(err)
+	err = n.Store(patterns[2:])
+	assert.NoError(err)
+
+	method := "foobar"
+	errString = "unsupported training method: %s"
+	err = n.Retrain(method)
+	assert.EqualError(err, fmt.Sprintf(errString, method))
+
+	err = n.Retrain("Storkey")
+	assert.NoError(err)
+	assert.Equal(len(patterns), n.Memorised())
+
+	fresh, err := NewNetwork(size, "storkey")
+	assert.NotNil(fresh)
+	assert.NoError(err)
+	err = fresh.Store(patterns)
+	assert.NoError(err)
+	assert.True(mat.EqualApprox(fresh.Weights(), n.Weights(), 1e-9))
+	assert.Equal(fresh.Capacity(), n.Capacity())
+}
+
+func TestStoreEvicting(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	patterns := randomPatterns(20, size, rand.New(rand.NewSource(1)))
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	errString := "network does not remember patterns"
+	err = n.StoreEvicting(patterns[0])
+	assert.EqualError(err, errString)
+
+	n, err = NewNetwork(size, "hebbian", WithRemember())
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString = "invalid pattern supplied: %v"
+	err = n.StoreEvicting(pattern)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %d"
+	err = n.StoreEvicting(pattern)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	for _, p := range patterns {
+		err = n.StoreEvicting(p)
+		assert.NoError(err)
+		assert.True(n.Memorised() <= n.Capacity())
+	}
+	capacity := n.Capacity()
+	assert.Equal(capacity, n.Memorised())
+
+	// network remembers only the most recent patterns
+	recent := patterns[len(patterns)-capacity:]
+	fresh, err := NewNetwork(size, "hebbian")
+	assert.NotNil(fresh)
+	assert.NoError(err)
+	err = fresh.Store(recent)
+	assert.NoError(err)
+	assert.True(mat.EqualApprox(fresh.Weights(), n.Weights(), 1e-9))
+
+	// the oldest pattern is no longer an attractor while the most recent one is
+	stable, err := n.Stable(patterns[0])
+	assert.NoError(err)
+	assert.False(stable)
+	stable, err = n.Stable(patterns[len(patterns)-1])
+	assert.NoError(err)
+	assert.True(stable)
+}
+
+func TestNearest(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 8
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	query := Encode([]float64{1, 1, 1, 1, -1, -1, -1, 1})
+	errString := "network does not remember patterns"
+	index, dist, err := n.Nearest(query)
+	assert.Equal(-1, index)
+	assert.Equal(-1, dist)
+	assert.EqualError(err, errString)
+
+	n, err = NewNetwork(size, "hebbian", WithRemember())
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store([]*Pattern{
+		Encode([]float64{1, 1, -1, -1, 1, 1, -1, -1}),
+		Encode([]float64{1, 1, 1, 1, -1, -1, -1, -1}),
+		Encode([]float64{1, -1, 1, -1, 1, -1, 1, -1}),
+	})
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString = "invalid pattern supplied: %v"
+	index, dist, err = n.Nearest(pattern)
+	assert.Equal(-1, index)
+	assert.Equal(-1, dist)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	index, dist, err = n.Nearest(pattern)
+	assert.Equal(-1, index)
+	assert.Equal(-1, dist)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	index, dist, err = n.Nearest(query)
+	assert.NoError(err)
+	assert.Equal(1, index)
+	assert.Equal(1, dist)
+}
+
+func TestMinSizeFor(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(0, MinSizeFor(0, "hebbian"))
+	assert.Equal(0, MinSizeFor(-1, "storkey"))
+
+	for _, method := range []string{"hebbian", "storkey"} {
+		for _, patterns := range []int{1, 2, 5, 10, 37, 100} {
+			size := MinSizeFor(patterns, method)
+			n, err := NewNetwork(size, method)
+			assert.NotNil(n)
+			assert.NoError(err)
+			assert.True(n.Capacity() >= patterns, method)
+			// smaller network does not fit the patterns
+			if size > 2 {
+				n, err = NewNetwork(size-1, method)
+				assert.NoError(err)
+				assert.True(n.Capacity() < patterns, method)
+			}
+		}
+	}
+}
+
+func TestMethod(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := NewNetwork(4, "Storkey")
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.Equal("storkey", n.Method())
+
+	err = n.Retrain("hebbian")
+	assert.Error(err)
+
+	n, err = NewNetwork(4, "storkey", WithRemember())
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Retrain("Hebbian")
+	assert.NoError(err)
+	assert.Equal("hebbian", n.Method())
+}
+
+func TestRestoreUntrained(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := NewNetwork(4, "storkey")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	pattern := Encode([]float64{1.0, -1.0, -1.0, 1.0})
+	for _, mode := range []string{"sync", "async"} {
+		res, err := n.Restore(pattern.clone(), mode, 1)
+		assert.Nil(res)
+		assert.True(errors.Is(err, ErrUntrained))
+		assert.Contains(err.Error(), "storkey")
+	}
+	res, err := n.RestoreRule(pattern.clone(), Greedy, 1)
+	assert.Nil(res)
+	assert.True(errors.Is(err, ErrUntrained))
+
+	// invalid input is reported first
+	res, err = n.Restore(pattern, "foobar", 1)
+	assert.Nil(res)
+	assert.EqualError(err, "unsupported mode: foobar")
+
+	err = n.Store([]*Pattern{pattern})
+	assert.NoError(err)
+	res, err = n.Restore(pattern.clone(), "async", 1)
+	assert.NoError(err)
+	assert.Equal(pattern.RawData(), res.RawData())
+}
+
+func TestTrained(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := NewNetwork(4, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.False(n.Trained())
+
+	err = n.Store(nil)
+	assert.Error(err)
+	assert.False(n.Trained())
+
+	err = n.Store([]*Pattern{Encode([]float64{1.0, -1.0, -1.0, 1.0})})
+	assert.NoError(err)
+	assert.True(n.Trained())
+}
+
+func TestNetworkString(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := NewNetwork(10, "Storkey")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	err = n.Store(randomPatterns(2, 10, rand.New(rand.NewSource(1))))
+	assert.NoError(err)
+
+	s := n.String()
+	assert.Contains(s, "size: 10")
+	assert.Contains(s, "method: storkey")
+	assert.Contains(s, "memorised: 2")
+	assert.Contains(s, fmt.Sprintf("capacity: %d", n.Capacity()))
+	assert.Contains(s, "load: 0.200")
+	assert.Equal(s, fmt.Sprint(n))
+}
+
+func TestInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 10
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	err = n.Store(randomPatterns(3, size, rand.New(rand.NewSource(1))))
+	assert.NoError(err)
+
+	info := n.Info()
+	rows, _ := n.Weights().Dims()
+	assert.Equal(rows, info.Size)
+	assert.Equal(n.Memorised(), info.Memorised)
+	assert.Equal(n.Capacity(), info.Capacity)
+	assert.Equal(n.Method(), info.Method)
+	assert.Equal(0.3, info.Load)
+}
+
+func TestEmptyPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	empty := &Pattern{}
+	err = n.Store([]*Pattern{empty})
+	assert.True(errors.Is(err, ErrEmptyPattern))
+	assert.Equal(0, n.Memorised())
+
+	err = n.Store([]*Pattern{Encode([]float64{1.0, -1.0, 1.0, -1.0})})
+	assert.NoError(err)
+
+	for _, mode := range []string{"sync", "async"} {
+		res, err := n.Restore(empty, mode, 10)
+		assert.Nil(res)
+		assert.True(errors.Is(err, ErrEmptyPattern))
+	}
+
+	res, err := n.RestoreRule(empty, AsyncSequential, 10)
+	assert.Nil(res)
+	assert.True(errors.Is(err, ErrEmptyPattern))
+
+	energy, err := n.Energy(empty)
+	assert.Equal(0.0, energy)
+	assert.True(errors.Is(err, ErrEmptyPattern))
 }
 
 func TestStore(t *testing.T) {
@@ -130,61 +583,1585 @@ func TestStore(t *testing.T) {
 	err = n.Store(patterns)
 	assert.NoError(err)
 	assert.Equal(n.Weights().At(0, 3), n.Weights().At(3, 0))
+
+	// unsupported training method
+	n, err = NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	n.method = "foobar"
+	errString = "unsupported training method: %s"
+	err = n.Store(patterns)
+	assert.EqualError(err, fmt.Sprintf(errString, n.method))
+	assert.Equal(0, n.Memorised())
+	assert.True(mat.Equal(mat.NewSymDense(size, nil), n.Weights()))
 }
 
-func TestRestore(t *testing.T) {
+func TestStoreUnique(t *testing.T) {
 	assert := assert.New(t)
 
-	size := 4
-	iters := 10
-	mode := "async"
-	method := "hebbian"
-	n, err := NewNetwork(size, method)
+	size := 20
+	patterns := randomPatterns(3, size, rand.New(rand.NewSource(1)))
+	inverse := Invert(patterns[1].clone())
+
+	n, err := NewNetwork(size, "storkey")
 	assert.NotNil(n)
 	assert.NoError(err)
 
-	data := []float64{1.0, -1.0, -1.0, 1.0}
-	v := mat.NewVecDense(len(data), data)
-	patterns := []*Pattern{{v: v}}
-	err = n.Store(patterns)
+	var invalid []*Pattern
+	errString := "invalid patterns supplied: %v"
+	stored, err := n.StoreUnique(invalid, false)
+	assert.Equal(0, stored)
+	assert.EqualError(err, fmt.Sprintf(errString, invalid))
+
+	input := []*Pattern{patterns[0], patterns[1], patterns[0].clone(), inverse, patterns[2], patterns[1]}
+	stored, err = n.StoreUnique(input, false)
 	assert.NoError(err)
+	assert.Equal(4, stored)
+	assert.Equal(4, n.Memorised())
 
-	var pattern *Pattern
-	errString := "invalid pattern supplied: %v"
-	res, err := n.Restore(pattern, mode, iters)
-	assert.Nil(res)
-	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+	// unique patterns produce the same weights as storing them directly
+	fresh, err := NewNetwork(size, "storkey")
+	assert.NotNil(fresh)
+	assert.NoError(err)
+	err = fresh.Store([]*Pattern{patterns[0], patterns[1], inverse, patterns[2]})
+	assert.NoError(err)
+	assert.True(mat.EqualApprox(fresh.Weights(), n.Weights(), 1e-9))
 
-	pattern = &Pattern{v: mat.NewVecDense(2, []float64{-1.0, 1.0})}
-	errString = "invalid pattern dimension: %v"
-	res, err = n.Restore(pattern, mode, iters)
-	assert.Nil(res)
-	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+	// inverse is a duplicate if requested
+	n, err = NewNetwork(size, "storkey")
+	assert.NotNil(n)
+	assert.NoError(err)
+	stored, err = n.StoreUnique(input, true)
+	assert.NoError(err)
+	assert.Equal(3, stored)
+	assert.Equal(3, n.Memorised())
 
-	iters = -5
-	data = []float64{1.0, -1.0, -1.0, 1.0}
-	v = mat.NewVecDense(len(data), data)
-	pattern = &Pattern{v: v}
-	errString = "invalid number of iterations: %d"
-	res, err = n.Restore(pattern, mode, iters)
-	assert.Nil(res)
-	assert.EqualError(err, fmt.Sprintf(errString, iters))
+	// remembered patterns are not stored again
+	n, err = NewNetwork(size, "storkey", WithRemember())
+	assert.NotNil(n)
+	assert.NoError(err)
+	stored, err = n.StoreUnique(patterns[:2], false)
+	assert.NoError(err)
+	assert.Equal(2, stored)
+	stored, err = n.StoreUnique(patterns, false)
+	assert.NoError(err)
+	assert.Equal(1, stored)
+	assert.Equal(3, n.Memorised())
+	stored, err = n.StoreUnique(patterns, false)
+	assert.NoError(err)
+	assert.Equal(0, stored)
+	assert.Equal(3, n.Memorised())
+}
+
+func TestStorePseudoInverse(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian", WithRemember())
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+
+	var invalid []*Pattern
+	errString := "invalid patterns supplied: %v"
+	err = n.StorePseudoInverse(invalid, 0.0)
+	assert.EqualError(err, fmt.Sprintf(errString, invalid))
+
+	lambda := -0.1
+	errString = "invalid regularization: %f"
+	err = n.StorePseudoInverse(patterns, lambda)
+	assert.EqualError(err, fmt.Sprintf(errString, lambda))
+
+	// correlated patterns differ in a few neurons only
+	correlated := make([]*Pattern, 5)
+	for i := range correlated {
+		correlated[i] = patterns[0].clone()
+		for j := 0; j < 3; j++ {
+			k := 3*i + j
+			correlated[i].RawData()[k] = -correlated[i].RawData()[k]
+		}
+	}
+	err = n.StorePseudoInverse(correlated, 0.0)
+	assert.NoError(err)
+	assert.Equal(len(correlated), n.Memorised())
+	assert.Len(n.remembered, len(correlated))
+	count, err := n.StableCount(correlated)
+	assert.NoError(err)
+	assert.Equal(len(correlated), count)
+	for i := 0; i < size; i++ {
+		assert.Equal(0.0, n.weights.At(i, i))
+	}
+
+	// linearly dependent patterns make the overlap matrix singular
+	dependent := append([]*Pattern{}, correlated...)
+	dependent = append(dependent, correlated[0].clone())
+	err = n.StorePseudoInverse(dependent, 0.0)
+	assert.Error(err)
+
+	// regularization stabilizes the inverse
+	err = n.StorePseudoInverse(dependent, 0.1)
+	assert.NoError(err)
+	count, err = n.StableCount(correlated)
+	assert.NoError(err)
+	assert.Equal(len(correlated), count)
+}
+
+func TestStoreBatched(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 20
+	patterns := randomPatterns(7, size, rand.New(rand.NewSource(1)))
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	batchSize := 0
+	errString := "invalid batch size: %d"
+	err = n.StoreBatched(patterns, batchSize, nil)
+	assert.EqualError(err, fmt.Sprintf(errString, batchSize))
+
+	invalid := []*Pattern{patterns[0], nil}
+	errString = "invalid pattern supplied: %v"
+	err = n.StoreBatched(invalid, 1, nil)
+	assert.EqualError(err, fmt.Sprintf(errString, invalid[1]))
+	// nothing is stored if any of the patterns is invalid
+	assert.False(n.Trained())
+
+	var done []int
+	err = n.StoreBatched(patterns, 3, func(d int) { done = append(done, d) })
+	assert.NoError(err)
+	assert.Equal([]int{3, 6, 7}, done)
+	assert.Equal(len(patterns), n.Memorised())
+
+	whole, err := NewNetwork(size, "hebbian")
+	assert.NotNil(whole)
+	assert.NoError(err)
+	err = whole.Store(patterns)
+	assert.NoError(err)
+
+	assert.True(mat.EqualApprox(whole.Weights(), n.Weights(), 1e-12))
+}
+
+func TestStoreNormalizeByCount(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 20
+	patterns := randomPatterns(4, size, rand.New(rand.NewSource(1)))
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	norm, err := NewNetwork(size, "hebbian", WithNormalizeByCount())
+	assert.NotNil(norm)
+	assert.NoError(err)
+	// store patterns incrementally to check the normalization is kept up to date
+	for _, p := range patterns {
+		err = norm.Store([]*Pattern{p})
+		assert.NoError(err)
+	}
+
+	w := mat.NewSymDense(size, nil)
+	w.ScaleSym(1/float64(len(patterns)), n.weights)
+	assert.True(mat.EqualApprox(w, norm.Weights(), 1e-12))
+
+	for _, p := range patterns {
+		energy, err := n.Energy(p)
+		assert.NoError(err)
+		normEnergy, err := norm.Energy(p)
+		assert.NoError(err)
+		assert.InDelta(energy/float64(len(patterns)), normEnergy, 1e-9)
+	}
+}
+
+func TestStoreMeanSubtract(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 200
+	rng := rand.New(rand.NewSource(1))
+	// biased patterns with 70% of active neurons
+	patterns := make([]*Pattern, 10)
+	for i := range patterns {
+		data := make([]float64, size)
+		for j := range data {
+			data[j] = -1.0
+			if rng.Float64() < 0.7 {
+				data[j] = 1.0
+			}
+		}
+		patterns[i] = Encode(data)
+	}
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	cov, err := NewNetwork(size, "hebbian", WithMeanSubtract())
+	assert.NotNil(cov)
+	assert.NoError(err)
+	assert.True(cov.meanSubtract)
+	err = cov.Store(patterns)
+	assert.NoError(err)
+
+	// weights are computed from the mean subtracted patterns
+	m := make([]float64, size)
+	for _, p := range patterns {
+		for i := range m {
+			m[i] += p.At(i) / float64(len(patterns))
+		}
+	}
+	expected := 0.0
+	for _, p := range patterns {
+		expected += (p.At(0) - m[0]) * (p.At(1) - m[1]) / float64(size)
+	}
+	assert.InDelta(expected, cov.Weights().At(0, 1), 1e-12)
+	// neuron biases are not modified
+	assert.Equal(make([]float64, size), cov.BiasSlice())
+
+	// errors counts the neurons restored to wrong states from noisy patterns
+	errors := func(n *Network) int {
+		count := 0
+		for _, p := range patterns {
+			noisy := p.clone()
+			for _, i := range rng.Perm(size)[:size/10] {
+				noisy.RawData()[i] = -noisy.At(i)
+			}
+			res, _ := n.restoreAsync(noisy, 10, rng)
+			count += res.distance(p)
+		}
+		return count
+	}
+	plain, covariance := 0, 0
+	for t := 0; t < 5; t++ {
+		plain += errors(n)
+		covariance += errors(cov)
+	}
+	assert.True(covariance < plain)
+}
+
+func TestStoreMeanSubtractBatches(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 30
+	patterns := randomPatterns(7, size, rand.New(rand.NewSource(1)))
+
+	for _, opts := range [][]Option{
+		{WithMeanSubtract()},
+		{WithMeanSubtract(), WithNormalizeByCount()},
+	} {
+		single, err := NewNetwork(size, "hebbian", opts...)
+		assert.NotNil(single)
+		assert.NoError(err)
+		err = single.Store(patterns)
+		assert.NoError(err)
+
+		// patterns are centred on the mean of all stored patterns regardless of the batches
+		batched, err := NewNetwork(size, "hebbian", opts...)
+		assert.NotNil(batched)
+		assert.NoError(err)
+		batched.bias.SetVec(0, 0.5)
+		err = batched.Store(patterns[:3])
+		assert.NoError(err)
+		err = batched.Store(patterns[3:])
+		assert.NoError(err)
+		assert.True(mat.EqualApprox(single.Weights(), batched.Weights(), 1e-9))
+		// user set bias is kept
+		assert.Equal(0.5, batched.bias.AtVec(0))
+	}
+}
+
+func TestStoreAndCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	for _, method := range []string{"hebbian", "storkey"} {
+		n, err := NewNetwork(size, method)
+		assert.NotNil(n)
+		assert.NoError(err)
+
+		var pattern *Pattern
+		errString := "invalid pattern supplied: %v"
+		energy, err := n.StoreAndCheck(pattern)
+		assert.Equal(0.0, energy)
+		assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+		pattern = Encode([]float64{1.0, -1.0, -1.0, 1.0})
+		energy, err = n.StoreAndCheck(pattern)
+		assert.NoError(err)
+		assert.True(energy < 0.0)
+
+		expected, err := n.Energy(pattern)
+		assert.NoError(err)
+		assert.Equal(expected, energy)
+	}
+}
+
+// storeStorkeyNaive stores patterns in w using Storkey learning computing local fields element-wise.
+// Patterns are stored one by one in a fresh weights matrix which is then added to w.
+func storeStorkeyNaive(w *mat.SymDense, patterns []*Pattern) {
+	dim := patterns[0].Len()
+	// weights matrix
+	s := mat.NewSymDense(dim, nil)
+	for _, p := range patterns {
+		// local fields are computed from the weights before the pattern is stored
+		old := mat.NewSymDense(dim, nil)
+		old.CopySym(s)
+		for i := 0; i < dim; i++ {
+			for j := i + 1; j < dim; j++ {
+				sum := p.At(i) * p.At(j)
+				sum -= p.At(i) * localField(old, p, j, i)
+				sum -= p.At(j) * localField(old, p, i, j)
+				s.SetSym(i, j, s.At(i, j)+sum/float64(dim))
+			}
+		}
+	}
+	// Add nwe weights matrix to network weights matrix
+	w.AddSym(w, s)
+}
+
+// localField calculates Storkey local field for a given pattern and returns it
+func localField(w *mat.SymDense, p *Pattern, i, j int) float64 {
+	sum := 0.0
+	// calculate sum for all but i and j neuron weights
+	for k := 0; k < p.Len(); k++ {
+		if k != i && k != j {
+			sum += w.At(i, k) * p.At(k)
+		}
+	}
+
+	return sum
+}
+
+func TestStoreStorkey(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 30
+	patterns := randomPatterns(6, size, rand.New(rand.NewSource(1)))
+
+	n, err := NewNetwork(size, "storkey")
+	assert.NotNil(n)
+	assert.NoError(err)
+	// store patterns in two batches to check the weights are accumulated
+	err = n.Store(patterns[:3])
+	assert.NoError(err)
+	err = n.Store(patterns[3:])
+	assert.NoError(err)
+
+	w := mat.NewSymDense(size, nil)
+	storeStorkeyNaive(w, patterns[:3])
+	storeStorkeyNaive(w, patterns[3:])
+
+	assert.True(mat.EqualApprox(w, n.Weights(), 1e-9))
+}
+
+func TestStoreScratch(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 20
+	patterns := randomPatterns(6, size, rand.New(rand.NewSource(1)))
+	for _, method := range []string{"hebbian", "storkey"} {
+		// storing the patterns one by one reuses the scratch buffers
+		n, err := NewNetwork(size, method)
+		assert.NotNil(n)
+		assert.NoError(err)
+		for _, p := range patterns {
+			err = n.Store([]*Pattern{p})
+			assert.NoError(err)
+		}
+
+		// fresh network allocates new scratch buffers for every store
+		fresh, err := NewNetwork(size, method)
+		assert.NotNil(fresh)
+		assert.NoError(err)
+		for _, p := range patterns {
+			fresh.wBuf, fresh.hBuf = nil, nil
+			err = fresh.Store([]*Pattern{p})
+			assert.NoError(err)
+		}
+		assert.Equal(fresh.Weights(), n.Weights(), method)
+	}
+}
+
+func BenchmarkStoreIncremental(b *testing.B) {
+	size := 100
+	patterns := randomPatterns(10, size, rand.New(rand.NewSource(1)))
+	for _, method := range []string{"hebbian", "storkey"} {
+		b.Run(method, func(b *testing.B) {
+			n, _ := NewNetwork(size, method)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = n.Store(patterns[i%len(patterns) : i%len(patterns)+1])
+			}
+		})
+	}
+}
+
+func BenchmarkStoreStorkey(b *testing.B) {
+	size := 100
+	patterns := randomPatterns(10, size, rand.New(rand.NewSource(1)))
+	for i := 0; i < b.N; i++ {
+		n, _ := NewNetwork(size, "storkey")
+		_ = n.Store(patterns)
+	}
+}
+
+func BenchmarkStoreStorkeyNaive(b *testing.B) {
+	size := 100
+	patterns := randomPatterns(10, size, rand.New(rand.NewSource(1)))
+	for i := 0; i < b.N; i++ {
+		w := mat.NewSymDense(size, nil)
+		storeStorkeyNaive(w, patterns)
+	}
+}
+
+func TestLocalFieldRaw(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	rng := rand.New(rand.NewSource(1))
+	n, err := NewNetwork(size, "storkey", WithRawAccess())
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.True(n.rawAccess)
+
+	err = n.Store(randomPatterns(5, size, rng))
+	assert.NoError(err)
+	err = n.SetExternalField(randomPatterns(1, size, rng)[0].RawData())
+	assert.NoError(err)
+
+	for _, p := range randomPatterns(5, size, rng) {
+		for i := 0; i < size; i++ {
+			n.rawAccess = false
+			expected := n.localField(i, p)
+			n.rawAccess = true
+			assert.Equal(expected, n.localField(i, p))
+			assert.Equal(expected, n.localFieldRaw(i, p))
+		}
+	}
+}
+
+func BenchmarkLocalField(b *testing.B) {
+	size := 500
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(10, size, rng)
+	for _, raw := range []bool{false, true} {
+		name := "At"
+		if raw {
+			name = "Raw"
+		}
+		b.Run(name, func(b *testing.B) {
+			n, _ := NewNetwork(size, "hebbian")
+			_ = n.Store(patterns)
+			n.rawAccess = raw
+			p := patterns[0]
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = n.localField(i%size, p)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	iters := 10
+	mode := "async"
+	method := "hebbian"
+	n, err := NewNetwork(size, method)
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	data := []float64{1.0, -1.0, -1.0, 1.0}
+	v := mat.NewVecDense(len(data), data)
+	patterns := []*Pattern{{v: v}}
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.Restore(pattern, mode, iters)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = &Pattern{v: mat.NewVecDense(2, []float64{-1.0, 1.0})}
+	errString = "invalid pattern dimension: %v"
+	res, err = n.Restore(pattern, mode, iters)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	iters = -5
+	data = []float64{1.0, -1.0, -1.0, 1.0}
+	v = mat.NewVecDense(len(data), data)
+	pattern = &Pattern{v: v}
+	errString = "invalid number of iterations: %d"
+	res, err = n.Restore(pattern, mode, iters)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	iters = 1
+	res, err = n.Restore(pattern, mode, iters)
+	assert.NotNil(res)
+	assert.NoError(err)
+
+	mode = "sync"
+	res, err = n.Restore(pattern, mode, iters)
+	assert.NotNil(res)
+	assert.NoError(err)
+
+	mode = "foobar"
+	errString = "unsupported mode: %s"
+	res, err = n.Restore(pattern, mode, iters)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, mode))
+}
+
+func TestRestoreTriState(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian", WithTriState(0.1))
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	err = n.Store([]*Pattern{Encode([]float64{1.0, 1.0, 1.0, 1.0})})
+	assert.NoError(err)
+
+	// local fields of the last two neurons cancel out
+	pattern := &Pattern{v: mat.NewVecDense(size, []float64{1.0, -1.0, 0.0, 0.0})}
+	res, err := n.Restore(pattern, "sync", 1)
+	assert.NoError(err)
+	assert.Equal([]float64{-1.0, 1.0, 0.0, 0.0}, res.RawData())
+
+	stable, err := n.Stable(&Pattern{v: mat.NewVecDense(size, nil)})
+	assert.NoError(err)
+	assert.True(stable)
+
+	// bipolar network polarizes all neurons
+	n, err = NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	err = n.Store([]*Pattern{Encode([]float64{1.0, 1.0, 1.0, 1.0})})
+	assert.NoError(err)
+
+	pattern = &Pattern{v: mat.NewVecDense(size, []float64{1.0, -1.0, 0.0, 0.0})}
+	res, err = n.Restore(pattern, "sync", 1)
+	assert.NoError(err)
+	assert.Equal([]float64{-1.0, 1.0, 1.0, 1.0}, res.RawData())
+}
+
+func TestRestoreStates(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 8
+	data := []float64{1, 1, -1, -1, 1, -1, 1, -1}
+	noisy := []float64{-1, 1, -1, -1, 1, -1, 1, 1}
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store([]*Pattern{EncodeStates(append([]float64{}, data...), 1.0, -1.0)})
+	assert.NoError(err)
+
+	n2, err := NewNetwork(size, "hebbian", WithStates(2.0, -2.0))
+	assert.NotNil(n2)
+	assert.NoError(err)
+	stored := EncodeStates(append([]float64{}, data...), 2.0, -2.0)
+	err = n2.Store([]*Pattern{stored})
+	assert.NoError(err)
+
+	// weights scale with the square of the neuron states
+	w := mat.NewSymDense(size, nil)
+	w.ScaleSym(4.0, n.weights)
+	assert.True(mat.EqualApprox(w, n2.Weights(), 1e-9))
+
+	for _, mode := range []string{"sync", "async"} {
+		res, err := n2.Restore(EncodeStates(append([]float64{}, noisy...), 2.0, -2.0), mode, 5)
+		assert.NoError(err)
+		assert.Equal(stored.RawData(), res.RawData())
+	}
+
+	// energy scales with the fourth power of the neuron states
+	energy, err := n.Energy(Encode(append([]float64{}, data...)))
+	assert.NoError(err)
+	energy2, err := n2.Energy(stored)
+	assert.NoError(err)
+	assert.InDelta(16*energy, energy2, 1e-9)
+}
+
+func TestRestoreTie(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	// connections of the last neuron cancel out for the pattern below
+	n.weights.SetSym(size-1, 0, 0.5)
+	n.weights.SetSym(size-1, 1, 0.5)
+	n.memorised = 1
+
+	pattern := Encode([]float64{1.0, -1.0, 1.0, -1.0})
+	sum := 0.0
+	for j := 0; j < size; j++ {
+		sum += n.weights.At(size-1, j) * pattern.At(j)
+	}
+	assert.Equal(n.bias.AtVec(size-1), sum)
+
+	for _, mode := range []string{"sync", "async"} {
+		res, err := n.Restore(pattern.clone(), mode, 1)
+		assert.NoError(err)
+		assert.Equal(-1.0, res.At(size-1))
+	}
+
+	// untrained network has zero local fields so no neuron flips
+	n, err = NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	pattern = Encode([]float64{-1.0, -1.0, 1.0, -1.0})
+	stable, err := n.Stable(pattern)
+	assert.NoError(err)
+	assert.True(stable)
+}
+
+func TestRestoreSync(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.RestoreSync(pattern, nil)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, err = n.RestoreSync(pattern, nil)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	// untrained network has zero local fields so all neurons tie
+	pattern = Encode([]float64{1.0, -1.0, 1.0, -1.0})
+	res, err = n.RestoreSync(pattern.clone(), nil)
+	assert.NoError(err)
+	assert.Equal(pattern.RawData(), res.RawData())
+
+	// the same seed breaks the ties the same way
+	outcomes := make(map[string]bool)
+	for seed := int64(0); seed < 10; seed++ {
+		res, err = n.RestoreSync(pattern.clone(), rand.New(rand.NewSource(seed)))
+		assert.NoError(err)
+		again, err := n.RestoreSync(pattern.clone(), rand.New(rand.NewSource(seed)))
+		assert.NoError(err)
+		assert.Equal(res.RawData(), again.RawData())
+		outcomes[res.String()] = true
+	}
+	// different seeds break the ties differently
+	assert.True(len(outcomes) > 1)
+
+	// tri-state neurons rest on ties
+	n, err = NewNetwork(size, "hebbian", WithTriState(0.0))
+	assert.NotNil(n)
+	assert.NoError(err)
+	res, err = n.RestoreSync(pattern.clone(), rand.New(rand.NewSource(1)))
+	assert.NoError(err)
+	assert.Equal([]float64{0.0, 0.0, 0.0, 0.0}, res.RawData())
+}
+
+func TestRestoreTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 16
+	mode := "async"
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	data := []float64{1, 1, -1, -1, 1, -1, 1, -1, -1, 1, 1, -1, -1, -1, 1, 1}
+	stored := Encode(data)
+	err = n.Store([]*Pattern{stored})
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.RestoreTimeout(pattern, mode, time.Second)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = &Pattern{v: mat.NewVecDense(2, []float64{-1.0, 1.0})}
+	errString = "invalid pattern dimension: %v"
+	res, err = n.RestoreTimeout(pattern, mode, time.Second)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	noisy := stored.clone()
+	noisy.RawData()[0] = -noisy.RawData()[0]
+	noisy.RawData()[5] = -noisy.RawData()[5]
+
+	timeout := time.Duration(0)
+	errString = "invalid timeout: %s"
+	res, err = n.RestoreTimeout(noisy.clone(), mode, timeout)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, timeout))
+
+	mode = "foobar"
+	errString = "unsupported mode: %s"
+	res, err = n.RestoreTimeout(noisy.clone(), mode, time.Second)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, mode))
+
+	for _, mode := range []string{"sync", "async"} {
+		// tiny timeout elapses before the network gets a chance to converge
+		res, err = n.RestoreTimeout(noisy.clone(), mode, time.Nanosecond)
+		assert.NoError(err)
+		assert.Equal(noisy.RawData(), res.RawData())
+
+		// generous timeout lets the network converge to the stored pattern
+		input := noisy.clone()
+		res, err = n.RestoreTimeout(input, mode, time.Minute)
+		assert.NoError(err)
+		assert.Equal(stored.RawData(), res.RawData())
+		// supplied pattern is not modified
+		assert.Equal(noisy.RawData(), input.RawData())
+		assert.True(res != input)
+	}
+}
+
+func TestRestoreTimeoutDivergence(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 16
+	n, err := NewNetwork(size, "hebbian", WithDivergenceGuard(3, 0.1))
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	data := []float64{1, 1, -1, -1, 1, -1, 1, -1, -1, 1, 1, -1, -1, -1, 1, 1}
+	stored := Encode(data)
+	err = n.Store([]*Pattern{stored})
+	assert.NoError(err)
+
+	// symmetric weights never diverge in async mode
+	noisy := stored.clone()
+	noisy.RawData()[0] = -noisy.RawData()[0]
+	res, err := n.RestoreTimeout(noisy, "async", time.Minute)
+	assert.NoError(err)
+	assert.Equal(stored.RawData(), res.RawData())
+
+	// inhibitory weights flip all neurons in every sync update, which
+	// makes the external field raise the energy every other update
+	size = 4
+	n, err = NewNetwork(size, "hebbian", WithDivergenceGuard(3, 0.1))
+	assert.NotNil(n)
+	assert.NoError(err)
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			n.weights.SetSym(i, j, -1.0)
+		}
+	}
+	err = n.SetExternalField([]float64{0.5, 0.5, 0.5, 0.5})
+	assert.NoError(err)
+
+	off := Encode([]float64{-1, -1, -1, -1})
+	res, err = n.RestoreTimeout(off.clone(), "sync", time.Minute)
+	assert.True(errors.Is(err, ErrDiverged))
+	// the lowest energy state found before the divergence is returned
+	assert.NotNil(res)
+	assert.Equal([]float64{1, 1, 1, 1}, res.RawData())
+	energy, err := n.Energy(res)
+	assert.NoError(err)
+	offEnergy, err := n.Energy(off)
+	assert.NoError(err)
+	assert.Less(energy, offEnergy)
+
+	testCases := []struct {
+		energies []float64
+		expected bool
+	}{
+		{[]float64{0.0}, false},
+		{[]float64{0.0, -1.0, -2.0}, false},
+		{[]float64{-1.0, -1.0, -0.95}, false},
+		{[]float64{-1.0, -2.0, -1.5}, true},
+		{[]float64{0.0, 1.0, 2.0}, true},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.expected, diverged(tc.energies, 0.1))
+	}
+}
+
+func TestRestoreTrace(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(4, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, deltas, err := n.RestoreTrace(pattern, 10)
+	assert.Nil(res)
+	assert.Nil(deltas)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, deltas, err = n.RestoreTrace(pattern, 10)
+	assert.Nil(res)
+	assert.Nil(deltas)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	res, deltas, err = n.RestoreTrace(patterns[0].clone(), iters)
+	assert.Nil(res)
+	assert.Nil(deltas)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	for _, p := range patterns {
+		iters = 5
+		res, deltas, err = n.RestoreTrace(n.addNoise(p.clone(), 30, rng), iters)
+		assert.NoError(err)
+		assert.NotNil(res)
+		assert.Len(deltas, iters)
+		for _, delta := range deltas {
+			assert.True(delta <= 1e-9)
+		}
+	}
+}
+
+func TestRestoreOverlapTrace(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian", WithSeed(1))
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(3, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, overlaps, err := n.RestoreOverlapTrace(pattern, patterns[0], 10)
+	assert.Nil(res)
+	assert.Nil(overlaps)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	res, overlaps, err = n.RestoreOverlapTrace(patterns[0].clone(), pattern, 10)
+	assert.Nil(res)
+	assert.Nil(overlaps)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, overlaps, err = n.RestoreOverlapTrace(patterns[0].clone(), pattern, 10)
+	assert.Nil(res)
+	assert.Nil(overlaps)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	errString = "invalid number of iterations: %d"
+	res, overlaps, err = n.RestoreOverlapTrace(patterns[0].clone(), patterns[0], 0)
+	assert.Nil(res)
+	assert.Nil(overlaps)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	noisy := patterns[0].clone()
+	for _, i := range rng.Perm(size)[:20] {
+		noisy.RawData()[i] = -noisy.At(i)
+	}
+	initial := mat.Dot(noisy.Vec(), patterns[0].Vec()) / float64(size)
+	res, overlaps, err = n.RestoreOverlapTrace(noisy, patterns[0], 5)
+	assert.NoError(err)
+	assert.Len(overlaps, 5)
+	assert.Equal(patterns[0].RawData(), res.RawData())
+	// overlap approaches 1 monotonically
+	assert.True(overlaps[0] > initial)
+	for i := 1; i < len(overlaps); i++ {
+		assert.True(overlaps[i] >= overlaps[i-1])
+	}
+	assert.Equal(1.0, overlaps[len(overlaps)-1])
+}
+
+func TestRestoreFlips(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian", WithSeed(1))
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(3, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, flips, err := n.RestoreFlips(pattern, 10)
+	assert.Nil(res)
+	assert.Equal(0, flips)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, flips, err = n.RestoreFlips(pattern, 10)
+	assert.Nil(res)
+	assert.Equal(0, flips)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	errString = "invalid number of iterations: %d"
+	res, flips, err = n.RestoreFlips(patterns[0].clone(), 0)
+	assert.Nil(res)
+	assert.Equal(0, flips)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	// stored pattern is a fixed point
+	res, flips, err = n.RestoreFlips(patterns[0].clone(), 10)
+	assert.NoError(err)
+	assert.Equal(0, flips)
+	assert.Equal(patterns[0].RawData(), res.RawData())
+
+	// noisy pattern needs at least as many flips as the number of corrupted neurons
+	noisy := n.addNoise(patterns[0].clone(), 10, rng)
+	d := noisy.distance(patterns[0])
+	res, flips, err = n.RestoreFlips(noisy, 10)
+	assert.NoError(err)
+	assert.Equal(patterns[0].RawData(), res.RawData())
+	assert.True(flips >= d)
+}
+
+func TestRestoreHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	history, err := n.RestoreHistory(pattern, 10, 1)
+	assert.Nil(history)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	history, err = n.RestoreHistory(pattern, 10, 1)
+	assert.Nil(history)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	history, err = n.RestoreHistory(patterns[0].clone(), iters, 1)
+	assert.Nil(history)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	every := 0
+	errString = "invalid sampling interval: %d"
+	history, err = n.RestoreHistory(patterns[0].clone(), 10, every)
+	assert.Nil(history)
+	assert.EqualError(err, fmt.Sprintf(errString, every))
+
+	testCases := []struct {
+		iters  int
+		every  int
+		length int
+	}{
+		{10, 1, 10},
+		{10, 2, 5},
+		{10, 3, 4},
+		{10, 20, 1},
+	}
+	for _, tc := range testCases {
+		input := n.addNoise(patterns[0].clone(), 10, rng)
+		history, err = n.RestoreHistory(input, tc.iters, tc.every)
+		assert.NoError(err)
+		assert.Len(history, tc.length)
+		// the last state is the final state
+		assert.Equal(input.RawData(), history[len(history)-1].RawData())
+		assert.Equal(patterns[0].RawData(), input.RawData())
+	}
+
+	// states are copies
+	history, err = n.RestoreHistory(n.addNoise(patterns[0].clone(), 10, rng), 2, 1)
+	assert.NoError(err)
+	assert.NotSame(history[0], history[1])
+}
+
+func TestRestoreSyncFields(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
 
-	iters = 1
-	res, err = n.Restore(pattern, mode, iters)
-	assert.NotNil(res)
+	rng := rand.New(rand.NewSource(1))
+	err = n.Store(randomPatterns(4, size, rng))
 	assert.NoError(err)
 
-	mode = "sync"
-	res, err = n.Restore(pattern, mode, iters)
-	assert.NotNil(res)
+	// all neurons must be updated from the local fields of the original state
+	input := randomPatterns(1, size, rng)[0]
+	h := mat.NewVecDense(size, nil)
+	h.MulVec(n.weights, input.Vec())
+	expected := make([]float64, size)
+	for i := range expected {
+		expected[i] = -1.0
+		if h.AtVec(i) >= 0.0 {
+			expected[i] = 1.0
+		}
+	}
+
+	res, err := n.Restore(input, "sync", 1)
 	assert.NoError(err)
+	assert.Equal(expected, res.RawData())
+}
 
-	mode = "foobar"
-	errString = "unsupported mode: %s"
-	res, err = n.Restore(pattern, mode, iters)
+func TestRestoreFieldOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.RestoreFieldOrder(pattern, 10)
 	assert.Nil(res)
-	assert.EqualError(err, fmt.Sprintf(errString, mode))
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, err = n.RestoreFieldOrder(pattern, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	res, err = n.RestoreFieldOrder(patterns[0].clone(), iters)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	res, err = n.RestoreFieldOrder(n.addNoise(patterns[0].clone(), 10, rng), 10)
+	assert.NoError(err)
+	assert.Equal(patterns[0].RawData(), res.RawData())
+
+	// count sweeps until convergence
+	fieldSweeps, randomSweeps := 0, 0
+	for _, p := range patterns {
+		noisy := n.addNoise(p.clone(), 20, rng)
+		for input := noisy.clone(); n.fieldOrderSweep(input); {
+			fieldSweeps++
+		}
+		for input := noisy.clone(); n.asyncSweep(input, rng); {
+			randomSweeps++
+		}
+	}
+	assert.True(fieldSweeps <= randomSweeps)
+}
+
+func TestRestorePrior(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.RestorePrior(pattern, patterns[0], 0.5, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	res, err = n.RestorePrior(patterns[0], pattern, 0.5, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, err = n.RestorePrior(patterns[0], pattern, 0.5, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	errString = "invalid alpha: %f"
+	for _, alpha := range []float64{-0.1, 1.1} {
+		res, err = n.RestorePrior(patterns[0], patterns[0], alpha, 10)
+		assert.Nil(res)
+		assert.EqualError(err, fmt.Sprintf(errString, alpha))
+	}
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	res, err = n.RestorePrior(patterns[0], patterns[0], 0.5, iters)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	// input with most of its bits flipped is closer to the inverted pattern
+	input := patterns[0].clone()
+	for i := 0; i < 70; i++ {
+		input.Set(i, -input.At(i))
+	}
+	// prior with a few bits flipped
+	prior := patterns[0].clone()
+	for i := size - 5; i < size; i++ {
+		prior.Set(i, -prior.At(i))
+	}
+	inputData := append([]float64(nil), input.RawData()...)
+	priorData := append([]float64(nil), prior.RawData()...)
+
+	raw, err := n.Restore(input.clone(), "async", 10)
+	assert.NoError(err)
+	res, err = n.RestorePrior(input, prior, 0.3, 10)
+	assert.NoError(err)
+	assert.Equal(patterns[0].RawData(), res.RawData())
+	assert.True(res.distance(patterns[0]) < raw.distance(patterns[0]))
+	// neither input nor prior are modified
+	assert.Equal(inputData, input.RawData())
+	assert.Equal(priorData, prior.RawData())
+
+	// alpha 1 ignores the prior
+	res, err = n.RestorePrior(input, prior, 1.0, 10)
+	assert.NoError(err)
+	assert.NotEqual(patterns[0].RawData(), res.RawData())
+}
+
+func TestRestoreEnsemble(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(10, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.RestoreEnsemble(pattern, 5, 10, rng)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, err = n.RestoreEnsemble(pattern, 5, 10, rng)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	errString = "invalid number of restarts: %d"
+	res, err = n.RestoreEnsemble(patterns[0], 0, 10, rng)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	errString = "invalid number of iterations: %d"
+	res, err = n.RestoreEnsemble(patterns[0], 5, 0, rng)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	// heavily corrupted patterns are recalled better by ensemble
+	single, ensemble := 0, 0
+	for _, p := range patterns {
+		for t := 0; t < 5; t++ {
+			noisy := p.clone()
+			for _, i := range rng.Perm(size)[:35] {
+				noisy.RawData()[i] = -noisy.At(i)
+			}
+			orig := noisy.clone()
+			res, err := n.RestoreEnsemble(noisy, 15, 10, rng)
+			assert.NoError(err)
+			assert.Equal(orig.RawData(), noisy.RawData())
+			ensemble += res.distance(p)
+			res, _ = n.restoreAsync(noisy, 10, rng)
+			single += res.distance(p)
+		}
+	}
+	assert.True(ensemble < single)
+
+	// restores of small networks start from differently perturbed inputs;
+	// untrained network keeps the inputs, so single restore returns its input
+	size = 8
+	n, err = NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	pattern = Encode([]float64{1, -1, 1, -1, 1, -1, 1, -1})
+	restored := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		res, err = n.RestoreEnsemble(pattern, 1, 10, rng)
+		assert.NoError(err)
+		assert.Equal(1, res.distance(pattern))
+		restored[fmt.Sprint(res.RawData())] = true
+	}
+	assert.True(len(restored) > 1)
+}
+
+func TestRestoreRegion(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian", WithSeed(1))
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(3, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	region := make([]int, 20)
+	for i := range region {
+		region[i] = 40 + i
+	}
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.RestoreRegion(pattern, region, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, err = n.RestoreRegion(pattern, region, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	errString = "invalid neuron index: %d"
+	for _, i := range []int{-1, size} {
+		res, err = n.RestoreRegion(patterns[0].clone(), []int{0, i}, 10)
+		assert.Nil(res)
+		assert.EqualError(err, fmt.Sprintf(errString, i))
+	}
+
+	errString = "duplicate neuron index: %d"
+	res, err = n.RestoreRegion(patterns[0].clone(), []int{1, 2, 1}, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 1))
+
+	errString = "invalid number of iterations: %d"
+	res, err = n.RestoreRegion(patterns[0].clone(), region, 0)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	// corrupt the pattern both inside and outside of the region
+	noisy := patterns[0].clone()
+	for _, i := range []int{5, 10, 45, 50, 55, 90} {
+		noisy.RawData()[i] = -noisy.At(i)
+	}
+	orig := noisy.clone()
+	res, err = n.RestoreRegion(noisy, region, 10)
+	assert.NoError(err)
+	for i := 0; i < size; i++ {
+		if i >= 40 && i < 60 {
+			assert.Equal(patterns[0].At(i), res.At(i))
+			continue
+		}
+		assert.Equal(orig.At(i), res.At(i))
+	}
+}
+
+func TestDetectCycle(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 2
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	// anti-correlated neurons oscillate when updated synchronously
+	err = n.Store([]*Pattern{Encode([]float64{1.0, -1.0})})
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	length, err := n.DetectCycle(pattern, 10)
+	assert.Equal(0, length)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0, 1.0})
+	errString = "invalid pattern dimension: %v"
+	length, err = n.DetectCycle(pattern, 10)
+	assert.Equal(0, length)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	maxSweeps := 0
+	errString = "invalid number of iterations: %d"
+	length, err = n.DetectCycle(Encode([]float64{1.0, -1.0}), maxSweeps)
+	assert.Equal(0, length)
+	assert.EqualError(err, fmt.Sprintf(errString, maxSweeps))
+
+	// stored pattern is a fixed point
+	length, err = n.DetectCycle(Encode([]float64{1.0, -1.0}), 10)
+	assert.NoError(err)
+	assert.Equal(1, length)
+
+	// both neurons flip at once: [1,1] -> [-1,-1] -> [1,1]
+	pattern = Encode([]float64{1.0, 1.0})
+	length, err = n.DetectCycle(pattern, 10)
+	assert.NoError(err)
+	assert.Equal(2, length)
+	assert.Equal([]float64{1.0, 1.0}, pattern.RawData())
+
+	// the cycle can't be detected within a single sweep
+	length, err = n.DetectCycle(pattern, 1)
+	assert.NoError(err)
+	assert.Equal(0, length)
+}
+
+func TestStable(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	data := []float64{1.0, -1.0, -1.0, 1.0}
+	v := mat.NewVecDense(len(data), data)
+	patterns := []*Pattern{{v: v}}
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	stable, err := n.Stable(pattern)
+	assert.False(stable)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = &Pattern{v: mat.NewVecDense(2, []float64{-1.0, 1.0})}
+	errString = "invalid pattern dimension: %v"
+	stable, err = n.Stable(pattern)
+	assert.False(stable)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	stable, err = n.Stable(patterns[0])
+	assert.True(stable)
+	assert.NoError(err)
+
+	pattern = Encode([]float64{1.0, 1.0, -1.0, 1.0})
+	stable, err = n.Stable(pattern)
+	assert.False(stable)
+	assert.NoError(err)
+	// pattern must not be modified
+	assert.Equal([]float64{1.0, 1.0, -1.0, 1.0}, pattern.RawData())
+}
+
+func TestStableCount(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 10
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	var patterns []*Pattern
+	errString := "invalid patterns supplied: %v"
+	count, err := n.StableCount(patterns)
+	assert.Equal(0, count)
+	assert.EqualError(err, fmt.Sprintf(errString, patterns))
+
+	patterns = []*Pattern{nil}
+	errString = "invalid pattern supplied: %v"
+	count, err = n.StableCount(patterns)
+	assert.Equal(0, count)
+	assert.EqualError(err, fmt.Sprintf(errString, patterns[0]))
+
+	// store way more patterns than network capacity
+	rng := rand.New(rand.NewSource(1))
+	patterns = make([]*Pattern, size)
+	for i := range patterns {
+		data := make([]float64, size)
+		for j := range data {
+			data[j] = rng.NormFloat64()
+		}
+		patterns[i] = Encode(data)
+	}
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	count, err = n.StableCount(patterns)
+	assert.NoError(err)
+	assert.True(count < len(patterns))
+}
+
+func TestEnergies(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 20
+	n, err := NewNetwork(size, "storkey")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+	err = n.SetExternalField(randomPatterns(1, size, rng)[0].RawData())
+	assert.NoError(err)
+
+	energies, err := n.Energies(nil)
+	assert.Nil(energies)
+	assert.Error(err)
+
+	errString := "invalid pattern dimension: %d"
+	energies, err = n.Energies([]*Pattern{patterns[0], Encode([]float64{1.0, -1.0})})
+	assert.Nil(energies)
+	assert.EqualError(err, fmt.Sprintf(errString, 2))
+
+	candidates := append(patterns, randomPatterns(5, size, rng)...)
+	energies, err = n.Energies(candidates)
+	assert.NoError(err)
+	assert.Len(energies, len(candidates))
+	for i, p := range candidates {
+		energy, err := n.Energy(p)
+		assert.NoError(err)
+		assert.InDelta(energy, energies[i], 1e-9)
+	}
+}
+
+func TestEnergyGap(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	gap, err := n.EnergyGap(pattern)
+	assert.Equal(0.0, gap)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	gap, err = n.EnergyGap(pattern)
+	assert.Equal(0.0, gap)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	// gap matches the energy differences of single flips
+	p := patterns[0]
+	energy, err := n.Energy(p)
+	assert.NoError(err)
+	min := math.Inf(1)
+	for i := 0; i < size; i++ {
+		flipped := p.clone()
+		flipped.RawData()[i] = -flipped.At(i)
+		e, err := n.Energy(flipped)
+		assert.NoError(err)
+		min = math.Min(min, e-energy)
+	}
+	gap, err = n.EnergyGap(p)
+	assert.NoError(err)
+	assert.InDelta(min, gap, 1e-9)
+
+	// stored patterns are strict local minima
+	for _, p := range patterns {
+		gap, err := n.EnergyGap(p)
+		assert.NoError(err)
+		assert.True(gap > 0.0)
+	}
+
+	// random patterns mostly are not
+	negative := 0
+	for _, p := range randomPatterns(20, size, rng) {
+		gap, err := n.EnergyGap(p)
+		assert.NoError(err)
+		if gap < 0.0 {
+			negative++
+		}
+	}
+	assert.True(negative > 15)
+}
+
+func TestNeuronInfluence(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 5
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	influence, err := n.NeuronInfluence(nil)
+	assert.Nil(influence)
+	assert.Error(err)
+
+	// neuron 2 is strongly connected to all the others which are weakly connected to each other
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			n.weights.SetSym(i, j, 0.1)
+		}
+		if i != 2 {
+			n.weights.SetSym(i, 2, 1.0)
+		}
+	}
+
+	patterns := randomPatterns(4, size, rand.New(rand.NewSource(1)))
+	influence, err = n.NeuronInfluence(patterns)
+	assert.NoError(err)
+	assert.Len(influence, size)
+	for i := range influence {
+		if i != 2 {
+			assert.True(influence[2] > influence[i])
+		}
+	}
+
+	// all the contributions of the all-on pattern are negative, so they sum to minus its energy
+	allOn := Encode([]float64{1.0, 1.0, 1.0, 1.0, 1.0})
+	energy, err := n.Energy(allOn)
+	assert.NoError(err)
+	influence, err = n.NeuronInfluence([]*Pattern{allOn})
+	assert.NoError(err)
+	sum := 0.0
+	for _, v := range influence {
+		sum += v
+	}
+	assert.InDelta(-energy, sum, 1e-9)
 }
 
 func TestEnergy(t *testing.T) {
@@ -215,3 +2192,40 @@ func TestEnergy(t *testing.T) {
 	assert.Equal(0.0, energy)
 	assert.NoError(err)
 }
+
+func TestNetworkAddNoise(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	rng := rand.New(rand.NewSource(1))
+	n, err := NewNetwork(size, "hebbian", WithStates(1.0, 0.0))
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	data := make([]float64, size)
+	for i := range data {
+		data[i] = float64(i % 2)
+	}
+	p := EncodeStates(data, 1.0, 0.0)
+
+	testCases := []struct {
+		pcnt  int
+		flips int
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{10, 5},
+		{50, 25},
+		{100, 50},
+	}
+
+	for _, tc := range testCases {
+		noisy := n.addNoise(p.clone(), tc.pcnt, rng)
+		// distinct neurons are flipped between the network states
+		assert.Equal(tc.flips, noisy.distance(p), tc.pcnt)
+		for i := 0; i < size; i++ {
+			assert.True(noisy.At(i) == 1.0 || noisy.At(i) == 0.0)
+		}
+	}
+}
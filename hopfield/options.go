@@ -0,0 +1,132 @@
+package hopfield
+
+// Options are Hopfield network options
+type Options struct {
+	// TriState enables tri-state neurons
+	TriState bool
+	// DeadBand is the tri-state neuron dead band
+	DeadBand float64
+	// On is the value of active neuron
+	On float64
+	// Off is the value of inactive neuron
+	Off float64
+	// DivergenceWindow is the number of sweeps watched by divergence guard
+	DivergenceWindow int
+	// DivergenceThreshold is the energy increase which triggers divergence guard
+	DivergenceThreshold float64
+	// Remember enables remembering of stored patterns
+	Remember bool
+	// MaxSize is the maximum allowed network size
+	MaxSize int
+	// NormalizeByCount enables normalization of Hebbian weights by the number of stored patterns
+	NormalizeByCount bool
+	// Seeded enables network pseudorandom number generator
+	Seeded bool
+	// Seed is the seed of network pseudorandom number generator
+	Seed int64
+	// RawAccess enables direct access to the raw weights data when computing local fields
+	RawAccess bool
+	// MeanSubtract enables subtraction of mean neuron activity in Hebbian learning
+	MeanSubtract bool
+	// RandomStart enables restoring from random initial state
+	RandomStart bool
+}
+
+// Option is functional network option
+type Option func(*Options)
+
+// WithTriState configures network neurons to be tri-state.
+// Besides +1 and -1, tri-state neurons can rest in inactive 0 state: a neuron is set to 0
+// when its local field is within deadBand distance from its bias.
+func WithTriState(deadBand float64) Option {
+	return func(o *Options) {
+		o.TriState = true
+		o.DeadBand = deadBand
+	}
+}
+
+// WithStates configures the values of active and inactive network neurons.
+// By default active neurons are set to +1 and inactive neurons are set to -1.
+// Patterns stored in and restored from the network should be encoded using the same values.
+func WithStates(on, off float64) Option {
+	return func(o *Options) {
+		o.On = on
+		o.Off = off
+	}
+}
+
+// WithDivergenceGuard enables restore divergence guard. The guard watches network energy of the last window sweeps
+// and aborts energy-aware restore with ErrDiverged when the energy rises by more than threshold above its minimum.
+func WithDivergenceGuard(window int, threshold float64) Option {
+	return func(o *Options) {
+		o.DivergenceWindow = window
+		o.DivergenceThreshold = threshold
+	}
+}
+
+// WithRemember configures network to remember copies of all the stored patterns in the order they were stored.
+// Remembered patterns allow the network to be retrained, at the cost of keeping the patterns in memory.
+func WithRemember() Option {
+	return func(o *Options) {
+		o.Remember = true
+	}
+}
+
+// WithMaxSize limits the network size to max neurons. Creating larger network fails before
+// any memory is allocated, which guards against sizes read from untrusted inputs.
+// Non-positive max means the size is not limited.
+func WithMaxSize(max int) Option {
+	return func(o *Options) {
+		o.MaxSize = max
+	}
+}
+
+// WithNormalizeByCount configures Hebbian learning to normalize the network weights by the number of stored patterns.
+// Normalized weights keep the network energy on the same scale regardless of how many patterns are stored,
+// which makes energies comparable across networks. The option has no effect on Storkey learning.
+func WithNormalizeByCount() Option {
+	return func(o *Options) {
+		o.NormalizeByCount = true
+	}
+}
+
+// WithMeanSubtract configures Hebbian learning to use covariance rule: mean activity of each neuron over
+// the stored patterns is subtracted from the pattern values before their outer product is added to the weights.
+// Covariance rule improves recall of biased patterns, i.e. the patterns with unbalanced active and inactive neurons.
+// The means are computed over all the patterns stored in the network, so storing the patterns in several batches
+// gives the same weights as storing them at once. Neuron biases are not modified.
+// The option has no effect on Storkey learning.
+func WithMeanSubtract() Option {
+	return func(o *Options) {
+		o.MeanSubtract = true
+	}
+}
+
+// WithRandomStart configures Restore and RestoreRule to start the network dynamics from a random state of on/off values
+// instead of the supplied pattern. The supplied pattern is overwritten by the random state and holds the state
+// the network converges to. Randomness is drawn from the network generator configured by WithSeed, if any.
+// Random starts sample the attractors of the network, which is useful for studying its spurious states.
+func WithRandomStart() Option {
+	return func(o *Options) {
+		o.RandomStart = true
+	}
+}
+
+// WithSeed configures network to draw the randomness of its restores from its own pseudorandom number generator
+// seeded with seed instead of the default source. State of the generator can be saved and restored
+// via RandState and SetRandState, which makes sequences of restores reproducible.
+func WithSeed(seed int64) Option {
+	return func(o *Options) {
+		o.Seeded = true
+		o.Seed = seed
+	}
+}
+
+// WithRawAccess configures network to compute local fields of single neurons by indexing the raw weights data
+// directly instead of accessing the weights element-wise via At. Both ways compute the same local fields;
+// direct indexing avoids At bounds checks and is faster on large networks, see BenchmarkLocalField.
+func WithRawAccess() Option {
+	return func(o *Options) {
+		o.RawAccess = true
+	}
+}
@@ -1,9 +1,14 @@
 package hopfield
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"image"
+	"image/color"
 	"image/draw"
+	"io"
+	"math"
 	"math/rand"
 
 	"gonum.org/v1/gonum/mat"
@@ -21,7 +26,7 @@ func (p *Pattern) String() string {
 	return fmt.Sprintf("%v", fa)
 }
 
-// Vec returns internal data vector
+// Vec returns internal data vector. The vector is live: modifying it modifies the pattern.
 func (p *Pattern) Vec() *mat.VecDense {
 	return p.v
 }
@@ -51,6 +56,220 @@ func (p *Pattern) Set(i int, val float64) error {
 	return nil
 }
 
+// CosineSimilarity computes cosine similarity between pattern p and other pattern and returns it.
+// For bipolar patterns of +1/-1 values cosine similarity is the same as their normalized overlap.
+// It returns error if other pattern is nil, if the patterns do not have the same dimension or if either of them has zero norm.
+func (p *Pattern) CosineSimilarity(other *Pattern) (float64, error) {
+	// other pattern can't be nil
+	if other == nil {
+		return 0.0, fmt.Errorf("invalid pattern supplied: %v", other)
+	}
+	// patterns must have the same dimension
+	if p.Len() != other.Len() {
+		return 0.0, fmt.Errorf("invalid pattern dimension: %d", other.Len())
+	}
+	// zero norm patterns have no direction
+	pNorm, oNorm := mat.Norm(p.v, 2), mat.Norm(other.v, 2)
+	if pNorm == 0.0 || oNorm == 0.0 {
+		return 0.0, fmt.Errorf("invalid pattern norm: %f", pNorm*oNorm)
+	}
+
+	return mat.Dot(p.v, other.v) / (pNorm * oNorm), nil
+}
+
+// Activity returns the fraction of active pattern neurons i.e. the neurons with positive value.
+// Activity of 0 or 1 after restore indicates degenerate convergence to all-off or all-on state.
+func (p *Pattern) Activity() float64 {
+	if p.Len() == 0 {
+		return 0.0
+	}
+	active := 0
+	for i := 0; i < p.Len(); i++ {
+		if p.At(i) > 0.0 {
+			active++
+		}
+	}
+
+	return float64(active) / float64(p.Len())
+}
+
+// Entropy returns binary Shannon entropy in bits of the fraction of active pattern neurons returned by Activity.
+// Patterns with balanced active and inactive neurons have entropy 1, patterns with all neurons in the same state have entropy 0.
+func (p *Pattern) Entropy() float64 {
+	a := p.Activity()
+	if a == 0.0 || a == 1.0 {
+		return 0.0
+	}
+
+	return -a*math.Log2(a) - (1-a)*math.Log2(1-a)
+}
+
+// Hash returns FNV-1a hash of the pattern. Pattern values are packed into bits before hashing:
+// positive values are packed as 1s, non-positive values as 0s, so equal binary patterns have the same hash.
+func (p *Pattern) Hash() uint64 {
+	h := fnv.New64a()
+	// hash the length so patterns which pack to the same bytes differ
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(p.Len()))
+	h.Write(buf)
+	h.Write(p.Pack())
+
+	return h.Sum64()
+}
+
+// Pack packs pattern values into bits and returns them. Positive values are packed as 1s, non-positive values
+// as 0s, least significant bit first, so each byte stores 8 consecutive values. Unpack reverses the packing.
+func (p *Pattern) Pack() []byte {
+	bits := make([]byte, (p.Len()+7)/8)
+	for i := 0; i < p.Len(); i++ {
+		if p.At(i) > 0.0 {
+			bits[i/8] |= 1 << uint(i%8)
+		}
+	}
+
+	return bits
+}
+
+// Unpack unpacks the first size bits packed by Pack into a pattern and returns it.
+// 1s are unpacked as +1 and 0s as -1. It returns error if size is not positive or if bits are too short.
+func Unpack(bits []byte, size int) (*Pattern, error) {
+	// pattern must have at least one value and bits must store all of them
+	if size <= 0 || len(bits) < (size+7)/8 {
+		return nil, fmt.Errorf("invalid pattern dimension: %d", size)
+	}
+	data := make([]float64, size)
+	for i := range data {
+		data[i] = -1.0
+		if bits[i/8]&(1<<uint(i%8)) != 0 {
+			data[i] = 1.0
+		}
+	}
+
+	return &Pattern{v: mat.NewVecDense(size, data)}, nil
+}
+
+// Shift interprets pattern p as a width x height image stored row by row, translates it by dx columns and dy rows
+// and returns the shifted pattern. Positive dx shifts the image right, positive dy shifts it down.
+// Cells vacated by the shift are filled with -1. Shift does not modify pattern p.
+// It returns error if width or height is non-positive or if width*height does not equal the pattern length.
+func (p *Pattern) Shift(width, height, dx, dy int) (*Pattern, error) {
+	// every value must have its pixel
+	if width <= 0 || height <= 0 || width*height != p.Len() {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", width, height)
+	}
+	data := make([]float64, p.Len())
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			srcX, srcY := x-dx, y-dy
+			if srcX < 0 || srcX >= width || srcY < 0 || srcY >= height {
+				data[y*width+x] = -1.0
+				continue
+			}
+			data[y*width+x] = p.At(srcY*width + srcX)
+		}
+	}
+
+	return &Pattern{
+		v: mat.NewVecDense(len(data), data),
+	}, nil
+}
+
+// Downsample interprets pattern p as a width x height image stored row by row, pools every factor x factor block
+// of its values into a single value and returns the downsampled pattern of (width/factor) x (height/factor) values.
+// Pooled value is 1 if the majority of the block values are positive, otherwise it is -1, so ties pool to -1.
+// Downsample does not modify pattern p.
+// It returns error if width or height is non-positive, if width*height does not equal the pattern length
+// or if factor is non-positive or does not divide both width and height.
+func (p *Pattern) Downsample(width, height, factor int) (*Pattern, error) {
+	// every value must have its pixel
+	if width <= 0 || height <= 0 || width*height != p.Len() {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", width, height)
+	}
+	// blocks must cover the whole image
+	if factor <= 0 || width%factor != 0 || height%factor != 0 {
+		return nil, fmt.Errorf("invalid downsampling factor: %d", factor)
+	}
+	w, h := width/factor, height/factor
+	data := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			votes := 0
+			for dy := 0; dy < factor; dy++ {
+				for dx := 0; dx < factor; dx++ {
+					if p.At((y*factor+dy)*width+x*factor+dx) > 0.0 {
+						votes++
+					} else {
+						votes--
+					}
+				}
+			}
+			data[y*w+x] = -1.0
+			if votes > 0 {
+				data[y*w+x] = 1.0
+			}
+		}
+	}
+
+	return &Pattern{
+		v: mat.NewVecDense(len(data), data),
+	}, nil
+}
+
+// Dropout sets pct percent of pseudorandomly chosen values of pattern p to -1, which simulates occluded
+// or missing inputs, and returns the result as a new pattern. Unlike AddNoise, Dropout does not flip the signs
+// of the values. Dropout does not modify pattern p. pct is clamped to [0, 100].
+// The values are chosen using rng. If rng is nil, default source is used.
+func (p *Pattern) Dropout(pct int, rng *rand.Rand) *Pattern {
+	if pct < 0 {
+		pct = 0
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	res := p.clone()
+	for _, i := range perm(rng, p.Len())[:pct*p.Len()/100] {
+		res.RawData()[i] = -1.0
+	}
+
+	return res
+}
+
+// clone returns a deep copy of the pattern
+func (p *Pattern) clone() *Pattern {
+	data := make([]float64, p.Len())
+	copy(data, p.RawData())
+
+	return &Pattern{
+		v: mat.NewVecDense(len(data), data),
+	}
+}
+
+// equal reports whether pattern p has the same values as other pattern
+func (p *Pattern) equal(other *Pattern) bool {
+	if p.Len() != other.Len() {
+		return false
+	}
+	for i := 0; i < p.Len(); i++ {
+		if p.At(i) != other.At(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// distance returns the number of positions at which the values of pattern p and other pattern differ
+func (p *Pattern) distance(other *Pattern) int {
+	d := 0
+	for i := 0; i < p.Len(); i++ {
+		if p.At(i) != other.At(i) {
+			d++
+		}
+	}
+
+	return d
+}
+
 // Len returns the length of the pattern
 func (p *Pattern) Len() int {
 	if p.v == nil {
@@ -64,11 +283,19 @@ func (p *Pattern) Len() int {
 // Encode modifies the data slice in place and returns a pointer to Pattern.
 // It panics if data is nil or zero-length slice!
 func Encode(data []float64) *Pattern {
+	return EncodeStates(data, 1.0, -1.0)
+}
+
+// EncodeStates encodes data to a pattern of values: on/off as follows:
+// Non-positive data items are set to off, positive values are set to on.
+// EncodeStates modifies the data slice in place and returns a pointer to Pattern.
+// It panics if data is nil or zero-length slice!
+func EncodeStates(data []float64, on, off float64) *Pattern {
 	for i := 0; i < len(data); i++ {
 		if data[i] <= 0.0 {
-			data[i] = -1.0
+			data[i] = off
 		} else {
-			data[i] = 1.0
+			data[i] = on
 		}
 	}
 	v := mat.NewVecDense(len(data), data)
@@ -78,6 +305,16 @@ func Encode(data []float64) *Pattern {
 	}
 }
 
+// PatternFromVec encodes values of vector v to a pattern using Encode and returns it.
+// PatternFromVec copies the values, so it does not modify v. It returns nil if v is nil or empty.
+func PatternFromVec(v *mat.VecDense) *Pattern {
+	if v == nil || v.IsEmpty() {
+		return nil
+	}
+
+	return Encode(mat.Col(nil, 0, v))
+}
+
 // AddNoise adds random noise to pattern p and returns it. Noise is added by flipping the sign of existing pattern value.
 // It allows to specify the percentage of noise via pcnt parameter. AddNoise modifies the pattern p in place.
 func AddNoise(p *Pattern, pcnt int) *Pattern {
@@ -93,21 +330,250 @@ func AddNoise(p *Pattern, pcnt int) *Pattern {
 	return p
 }
 
+// OverlapMatrix computes normalized overlaps of all pairs of patterns and returns them in a symmetric matrix.
+// Overlap of two patterns is the dot product of their values divided by their dimension.
+// It returns error if patterns is nil, if any of the patterns is nil or if the patterns do not have the same dimension.
+func OverlapMatrix(patterns []*Pattern) (*mat.SymDense, error) {
+	// patterns can't be nil
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("invalid patterns supplied: %v", patterns)
+	}
+	for _, p := range patterns {
+		// nil patterns are invalid
+		if p == nil {
+			return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+		}
+		// all patterns must have the same dimension
+		if p.Len() != patterns[0].Len() {
+			return nil, fmt.Errorf("invalid pattern dimension: %d", p.Len())
+		}
+	}
+	overlaps := mat.NewSymDense(len(patterns), nil)
+	for i := range patterns {
+		for j := i; j < len(patterns); j++ {
+			overlaps.SetSym(i, j, mat.Dot(patterns[i].v, patterns[j].v)/float64(patterns[i].Len()))
+		}
+	}
+
+	return overlaps, nil
+}
+
+// SplitPatterns randomly splits patterns into train and test patterns and returns them.
+// Patterns are shuffled using rng; if rng is nil, default source is used. trainFrac is the fraction of patterns
+// which end up in the train set. SplitPatterns does not copy the patterns, only the slices are new.
+// It returns error if patterns is nil or if trainFrac is not in (0,1) interval.
+func SplitPatterns(patterns []*Pattern, trainFrac float64, rng *rand.Rand) (train, test []*Pattern, err error) {
+	// patterns can't be nil
+	if len(patterns) == 0 {
+		return nil, nil, fmt.Errorf("invalid patterns supplied: %v", patterns)
+	}
+	// train fraction must be in the open unit interval
+	if trainFrac <= 0.0 || trainFrac >= 1.0 {
+		return nil, nil, fmt.Errorf("invalid train fraction: %f", trainFrac)
+	}
+	seq := perm(rng, len(patterns))
+	count := int(trainFrac * float64(len(patterns)))
+	train = make([]*Pattern, 0, count)
+	test = make([]*Pattern, 0, len(patterns)-count)
+	for i, j := range seq {
+		if i < count {
+			train = append(train, patterns[j])
+		} else {
+			test = append(test, patterns[j])
+		}
+	}
+
+	return train, test, nil
+}
+
+// Morph generates steps patterns which gradually transform pattern a into pattern b and returns them.
+// The first pattern is a copy of a, the last one is a copy of b. The patterns in between are generated by
+// progressively setting the values which differ between a and b to the values of b in a random order.
+// It returns error if either of the patterns is nil, if they do not have the same dimension or if steps is less than 2.
+func Morph(a, b *Pattern, steps int) ([]*Pattern, error) {
+	// patterns can't be nil
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("invalid patterns supplied: %v, %v", a, b)
+	}
+	// patterns must have the same dimension
+	if a.Len() != b.Len() {
+		return nil, fmt.Errorf("invalid pattern dimension: %d", b.Len())
+	}
+	// we need at least the first and the last pattern
+	if steps < 2 {
+		return nil, fmt.Errorf("invalid number of steps: %d", steps)
+	}
+	// diff contains the positions in which the patterns differ in a random order
+	var diff []int
+	for _, i := range rand.Perm(a.Len()) {
+		if a.At(i) != b.At(i) {
+			diff = append(diff, i)
+		}
+	}
+	patterns := make([]*Pattern, steps)
+	current, done := a.clone(), 0
+	for k := range patterns {
+		// number of values which have been set to the values of b so far
+		count := int(math.Round(float64(k*len(diff)) / float64(steps-1)))
+		for ; done < count; done++ {
+			current.RawData()[diff[done]] = b.At(diff[done])
+		}
+		patterns[k] = current.clone()
+	}
+
+	return patterns, nil
+}
+
+// Invert flips the sign of all values of pattern p and returns it. Invert modifies the pattern p in place.
+// Inverted pattern is always an attractor of the network which stores the original pattern.
+func Invert(p *Pattern) *Pattern {
+	p.v.ScaleVec(-1.0, p.v)
+
+	return p
+}
+
+// TransposePattern interprets pattern p as a width x height image stored row by row, transposes it and returns
+// the transposed height x width image pattern stored row by row. Image2Pattern flattens images row by row,
+// so patterns of images flattened column by column are converted to match by transposing them
+// as height x width images. TransposePattern does not modify pattern p.
+// It returns error if width or height is non-positive or if width*height does not equal the pattern length.
+func TransposePattern(p *Pattern, width, height int) (*Pattern, error) {
+	// every value must have its pixel
+	if width <= 0 || height <= 0 || width*height != p.Len() {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", width, height)
+	}
+	data := make([]float64, p.Len())
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			data[x*height+y] = p.At(y*width + x)
+		}
+	}
+
+	return &Pattern{
+		v: mat.NewVecDense(len(data), data),
+	}, nil
+}
+
+// Augment interprets pattern p as a width x height image stored row by row and returns a copy of p followed by
+// its copies shifted by Shift by all the combinations of dx and dy in [-maxShift, maxShift], except 0, 0.
+// Storing the shifted copies makes the network tolerate small translations of the pattern.
+// Augment does not modify pattern p. It returns nil if maxShift is negative or if the image dimensions are invalid.
+func Augment(p *Pattern, width, height, maxShift int) []*Pattern {
+	if maxShift < 0 {
+		return nil
+	}
+	patterns := []*Pattern{p.clone()}
+	for dy := -maxShift; dy <= maxShift; dy++ {
+		for dx := -maxShift; dx <= maxShift; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			shifted, err := p.Shift(width, height, dx, dy)
+			if err != nil {
+				return nil
+			}
+			patterns = append(patterns, shifted)
+		}
+	}
+
+	return patterns
+}
+
 // Image2Pattern transforms img raw data into binary encoded pattern that can be used in Hopfield Network
 // It first turns the image into a Grey scaled image and then encodes its pixels into binary values of -1/+1
+// Image colors are alpha-premultiplied when converted to Grey scale, so fully transparent pixels are always
+// encoded to -1 regardless of their color.
 func Image2Pattern(img image.Image) *Pattern {
+	return image2Pattern(img, 0)
+}
+
+// Image2PatternOtsu transforms img raw data into binary encoded pattern using automatically chosen threshold.
+// It first turns the image into a Grey scaled image and computes Otsu threshold of its histogram, which maximizes
+// the variance between dark and bright pixels. Pixels brighter than the threshold are encoded to +1, the rest to -1.
+func Image2PatternOtsu(img image.Image) *Pattern {
+	// convert image to Gray scaled image
+	imGray := image.NewGray(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(imGray, imGray.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return image2Pattern(imGray, otsuThreshold(imGray.Pix))
+}
+
+// otsuThreshold computes Otsu threshold of Grey scaled pixels pix and returns it.
+// Pixels up to the threshold form the dark class, the rest form the bright class.
+// If all the pixels have the same intensity, they can't be split and zero threshold is returned.
+func otsuThreshold(pix []uint8) uint8 {
+	var hist [256]float64
+	sum := 0.0
+	for _, v := range pix {
+		hist[v]++
+		sum += float64(v)
+	}
+	total := float64(len(pix))
+	// dark class weight and sum of its pixels
+	weight, sumDark := 0.0, 0.0
+	threshold, maxVar := 0, -1.0
+	for t := 0; t < 256; t++ {
+		weight += hist[t]
+		sumDark += float64(t) * hist[t]
+		if weight == 0.0 || weight == total {
+			continue
+		}
+		meanDark := sumDark / weight
+		meanBright := (sum - sumDark) / (total - weight)
+		// between class variance
+		v := weight * (total - weight) * (meanDark - meanBright) * (meanDark - meanBright)
+		if v > maxVar {
+			threshold, maxVar = t, v
+		}
+	}
+
+	return uint8(threshold)
+}
+
+// DecodeImagePattern decodes image from r and transforms it into binary encoded pattern which it returns.
+// Image pixels are Grey scaled first: pixels brighter than threshold are encoded to +1, the rest to -1.
+// Only the image formats registered via image.RegisterFormat can be decoded.
+// It returns error if the image fails to be decoded.
+func DecodeImagePattern(r io.Reader, threshold uint8) (*Pattern, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return image2Pattern(img, threshold), nil
+}
+
+// image2Pattern transforms img Grey scaled pixels brighter than threshold to +1 and the rest to -1
+func image2Pattern(img image.Image, threshold uint8) *Pattern {
 	// convert image to Gray scaled image
 	imGray := image.NewGray(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
 	draw.Draw(imGray, imGray.Bounds(), img, img.Bounds().Min, draw.Src)
 	// convert pixels into floats
 	pattern := make([]float64, len(imGray.Pix))
 	for i := range imGray.Pix {
-		pattern[i] = float64(imGray.Pix[i])
+		pattern[i] = float64(imGray.Pix[i]) - float64(threshold)
 	}
 
 	return Encode(pattern)
 }
 
+// IsBinaryImage reports whether img is a black and white image. With zero threshold only pure black and white
+// pixels of Gray scaled img are accepted, so converting the image to pattern via Image2Pattern and back via
+// Pattern2Image is lossless. Positive threshold tolerates pixels which are at most threshold away from either
+// black or white. Such pixels are converted to pure black or white, so the round trip is not lossless then.
+func IsBinaryImage(img image.Image, threshold uint8) bool {
+	// convert image to Gray scaled image
+	imGray := image.NewGray(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(imGray, imGray.Bounds(), img, img.Bounds().Min, draw.Src)
+	for _, pix := range imGray.Pix {
+		if pix > threshold && pix < 255-threshold {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Pattern2Image turns pattern p to a *lossy* Gray scaled image.
 // Data to pixel transformation is lossy: non-positive elements are transformed to 0, otherwise 255
 func Pattern2Image(p *Pattern, r image.Rectangle) image.Image {
@@ -126,3 +592,86 @@ func Pattern2Image(p *Pattern, r image.Rectangle) image.Image {
 
 	return img
 }
+
+// Pattern2ImageColor renders pattern p as RGBA image with r bounds and returns it. Pixels of positive
+// pattern elements are set to onColor, the rest of the pixels are set to offColor.
+// It returns error if the pattern is nil or if r area does not match the pattern dimension.
+func Pattern2ImageColor(p *Pattern, r image.Rectangle, onColor, offColor color.Color) (image.Image, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// every neuron must have its pixel
+	if r.Dx()*r.Dy() != p.Len() {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", r.Dx(), r.Dy())
+	}
+	img := image.NewRGBA(r)
+	for i := 0; i < p.Len(); i++ {
+		c := offColor
+		if p.At(i) > 0.0 {
+			c = onColor
+		}
+		img.Set(r.Min.X+i%r.Dx(), r.Min.Y+i/r.Dx(), c)
+	}
+
+	return img, nil
+}
+
+// ToSquareImage converts pattern p to a square Gray image and returns it.
+// Side of the image is computed as the square root of the pattern length.
+// It returns error if the pattern length is not a perfect square.
+func (p *Pattern) ToSquareImage() (image.Image, error) {
+	side := int(math.Round(math.Sqrt(float64(p.Len()))))
+	// every value must have its pixel
+	if side == 0 || side*side != p.Len() {
+		return nil, fmt.Errorf("invalid pattern dimension: %d", p.Len())
+	}
+
+	return Pattern2Image(p, image.Rect(0, 0, side, side)), nil
+}
+
+// DiffImage renders the difference between patterns a and b as RGBA image with r bounds and returns it.
+// Pixels of the neurons which have the same value in both patterns are black, the rest of the pixels are red.
+// It returns error if either of the patterns is nil, if they do not have the same dimension or if r area does not match it.
+func DiffImage(a, b *Pattern, r image.Rectangle) (image.Image, error) {
+	// patterns can't be nil
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("invalid patterns supplied: %v, %v", a, b)
+	}
+	// patterns must have the same dimension
+	if a.Len() != b.Len() {
+		return nil, fmt.Errorf("invalid pattern dimension: %d", b.Len())
+	}
+	// every neuron must have its pixel
+	if r.Dx()*r.Dy() != a.Len() {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", r.Dx(), r.Dy())
+	}
+	img := image.NewRGBA(r)
+	draw.Draw(img, img.Bounds(), image.Black, image.Point{}, draw.Src)
+	for i := 0; i < a.Len(); i++ {
+		if a.At(i) != b.At(i) {
+			img.Set(r.Min.X+i%r.Dx(), r.Min.Y+i/r.Dx(), color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	return img, nil
+}
+
+// RecallStrip renders input pattern, restored pattern and their difference rendered by DiffImage side by side
+// and returns the rendered strip. Each of the three tiles is rendered with r dimensions and the tiles are
+// separated by gray columns of a single pixel. It returns the same errors as DiffImage.
+func RecallStrip(input, restored *Pattern, r image.Rectangle) (image.Image, error) {
+	w, h := r.Dx(), r.Dy()
+	tile := image.Rect(0, 0, w, h)
+	diff, err := DiffImage(input, restored, tile)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 3*w+2, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{Y: 128}), image.Point{}, draw.Src)
+	for i, t := range []image.Image{Pattern2Image(input, tile), Pattern2Image(restored, tile), diff} {
+		draw.Draw(img, image.Rect(i*(w+1), 0, i*(w+1)+w, h), t, image.Point{}, draw.Src)
+	}
+
+	return img, nil
+}
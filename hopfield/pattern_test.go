@@ -1,9 +1,13 @@
 package hopfield
 
 import (
+	"bytes"
 	"fmt"
 	"image"
+	"image/color"
 	"image/draw"
+	"image/png"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -74,6 +78,259 @@ func TestLen(t *testing.T) {
 	assert.Equal(p.Len(), 0)
 }
 
+func TestCosineSimilarity(t *testing.T) {
+	assert := assert.New(t)
+
+	p := Encode([]float64{1.0, 1.0, -1.0, -1.0})
+
+	var other *Pattern
+	errString := "invalid pattern supplied: %v"
+	sim, err := p.CosineSimilarity(other)
+	assert.Equal(0.0, sim)
+	assert.EqualError(err, fmt.Sprintf(errString, other))
+
+	other = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %d"
+	sim, err = p.CosineSimilarity(other)
+	assert.Equal(0.0, sim)
+	assert.EqualError(err, fmt.Sprintf(errString, other.Len()))
+
+	other = &Pattern{v: mat.NewVecDense(4, nil)}
+	errString = "invalid pattern norm: %f"
+	sim, err = p.CosineSimilarity(other)
+	assert.Equal(0.0, sim)
+	assert.EqualError(err, fmt.Sprintf(errString, 0.0))
+
+	testCases := []struct {
+		other    []float64
+		expected float64
+	}{
+		{[]float64{1.0, 1.0, -1.0, -1.0}, 1.0},
+		{[]float64{-1.0, -1.0, 1.0, 1.0}, -1.0},
+		{[]float64{1.0, -1.0, 1.0, -1.0}, 0.0},
+		{[]float64{1.0, 1.0, 1.0, -1.0}, 0.5},
+	}
+
+	for _, tc := range testCases {
+		sim, err = p.CosineSimilarity(Encode(tc.other))
+		assert.NoError(err)
+		assert.InDelta(tc.expected, sim, 0.0001)
+	}
+}
+
+func TestActivity(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		data     []float64
+		expected float64
+	}{
+		{[]float64{1.0, 1.0, 1.0, 1.0}, 1.0},
+		{[]float64{-1.0, -1.0, -1.0, -1.0}, 0.0},
+		{[]float64{1.0, -1.0, -1.0, -1.0}, 0.25},
+		{[]float64{1.0, 1.0, -1.0}, 2.0 / 3.0},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(tc.expected, Encode(tc.data).Activity(), 0.0001)
+	}
+
+	p := &Pattern{}
+	assert.Equal(0.0, p.Activity())
+}
+
+func TestEntropy(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		data     []float64
+		expected float64
+	}{
+		{[]float64{1.0, -1.0, 1.0, -1.0}, 1.0},
+		{[]float64{1.0, 1.0, 1.0, 1.0}, 0.0},
+		{[]float64{-1.0, -1.0, -1.0, -1.0}, 0.0},
+		{[]float64{1.0, -1.0, -1.0, -1.0}, 0.8113},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(tc.expected, Encode(tc.data).Entropy(), 0.0001)
+	}
+
+	p := &Pattern{}
+	assert.Equal(0.0, p.Entropy())
+}
+
+func TestHash(t *testing.T) {
+	assert := assert.New(t)
+
+	p := Encode([]float64{1.0, 1.0, -1.0, -1.0, 1.0, -1.0, 1.0, 1.0, -1.0})
+	other := Encode([]float64{1.0, 1.0, -1.0, -1.0, 1.0, -1.0, 1.0, 1.0, -1.0})
+	assert.Equal(p.Hash(), other.Hash())
+
+	for i := 0; i < other.Len(); i++ {
+		other.RawData()[i] = -other.RawData()[i]
+		assert.NotEqual(p.Hash(), other.Hash())
+		other.RawData()[i] = -other.RawData()[i]
+	}
+
+	// patterns packed to the same bytes but with different length
+	short := Encode([]float64{-1.0, -1.0})
+	long := Encode([]float64{-1.0, -1.0, -1.0})
+	assert.NotEqual(short.Hash(), long.Hash())
+}
+
+func TestPackUnpack(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []float64{1.0, -1.0, -1.0, 1.0, 1.0, 1.0, -1.0, -1.0, 1.0, -1.0}
+	p := Encode(data)
+	bits := p.Pack()
+	assert.Equal([]byte{0x39, 0x01}, bits)
+
+	unpacked, err := Unpack(bits, p.Len())
+	assert.NoError(err)
+	assert.Equal(p.RawData(), unpacked.RawData())
+
+	// non-positive values are unpacked as -1
+	unpacked, err = Unpack(Encode([]float64{0.5, 0.0, -2.0}).Pack(), 3)
+	assert.NoError(err)
+	assert.Equal([]float64{1.0, -1.0, -1.0}, unpacked.RawData())
+
+	errString := "invalid pattern dimension: %d"
+	for _, size := range []int{0, -1, 17} {
+		unpacked, err = Unpack(bits, size)
+		assert.Nil(unpacked)
+		assert.EqualError(err, fmt.Sprintf(errString, size))
+	}
+}
+
+func TestShift(t *testing.T) {
+	assert := assert.New(t)
+
+	// 3x3 plus glyph
+	p := Encode([]float64{
+		-1, 1, -1,
+		1, 1, 1,
+		-1, 1, -1,
+	})
+
+	errString := "invalid image dimensions: %dx%d"
+	res, err := p.Shift(2, 4, 1, 0)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 2, 4))
+
+	res, err = p.Shift(0, 9, 1, 0)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 0, 9))
+
+	res, err = p.Shift(3, 3, 1, 0)
+	assert.NoError(err)
+	assert.Equal([]float64{
+		-1, -1, 1,
+		-1, 1, 1,
+		-1, -1, 1,
+	}, res.RawData())
+
+	res, err = p.Shift(3, 3, 0, -1)
+	assert.NoError(err)
+	assert.Equal([]float64{
+		1, 1, 1,
+		-1, 1, -1,
+		-1, -1, -1,
+	}, res.RawData())
+
+	res, err = p.Shift(3, 3, 0, 0)
+	assert.NoError(err)
+	assert.Equal(p.RawData(), res.RawData())
+
+	// shifting the glyph out of the image clears it
+	res, err = p.Shift(3, 3, 3, 0)
+	assert.NoError(err)
+	assert.Equal([]float64{-1, -1, -1, -1, -1, -1, -1, -1, -1}, res.RawData())
+
+	// original pattern is not modified
+	assert.Equal([]float64{-1, 1, -1, 1, 1, 1, -1, 1, -1}, p.RawData())
+}
+
+func TestDownsample(t *testing.T) {
+	assert := assert.New(t)
+
+	p := Encode([]float64{
+		1, 1, -1, -1,
+		1, -1, -1, -1,
+		-1, 1, 1, 1,
+		-1, 1, 1, -1,
+	})
+
+	errString := "invalid image dimensions: %dx%d"
+	res, err := p.Downsample(2, 4, 2)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 2, 4))
+
+	errString = "invalid downsampling factor: %d"
+	for _, factor := range []int{0, 3} {
+		res, err = p.Downsample(4, 4, factor)
+		assert.Nil(res)
+		assert.EqualError(err, fmt.Sprintf(errString, factor))
+	}
+
+	// bottom left block is a tie
+	res, err = p.Downsample(4, 4, 2)
+	assert.NoError(err)
+	assert.Equal([]float64{1, -1, -1, 1}, res.RawData())
+
+	res, err = p.Downsample(4, 4, 1)
+	assert.NoError(err)
+	assert.Equal(p.RawData(), res.RawData())
+
+	// the whole image is a tie
+	res, err = p.Downsample(4, 4, 4)
+	assert.NoError(err)
+	assert.Equal([]float64{-1}, res.RawData())
+}
+
+func TestDropout(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 200
+	data := make([]float64, size)
+	for i := range data {
+		data[i] = 1.0
+	}
+	p := Encode(data)
+
+	rng := rand.New(rand.NewSource(1))
+	for _, pct := range []int{0, 10, 25, 100} {
+		res := p.Dropout(pct, rng)
+		dropped := 0
+		for i := 0; i < size; i++ {
+			if res.At(i) == -1.0 {
+				dropped++
+			}
+		}
+		assert.Equal(pct*size/100, dropped)
+	}
+
+	// values which are already -1 stay -1 and the signs are never flipped
+	p = randomPatterns(1, size, rng)[0]
+	res := p.Dropout(50, rng)
+	for i := 0; i < size; i++ {
+		if p.At(i) == -1.0 {
+			assert.Equal(-1.0, res.At(i))
+		}
+	}
+
+	// out of range percentages are clamped
+	res = p.Dropout(-10, rng)
+	assert.Equal(p.RawData(), res.RawData())
+	res = p.Dropout(110, rng)
+	for i := 0; i < size; i++ {
+		assert.Equal(-1.0, res.At(i))
+	}
+	// original pattern is not modified
+	assert.NotEqual(res.RawData(), p.RawData())
+}
+
 func TestEncode(t *testing.T) {
 	assert := assert.New(t)
 
@@ -91,6 +348,36 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeStates(t *testing.T) {
+	assert := assert.New(t)
+
+	res := EncodeStates([]float64{1.0, -10.0, 0.0, 3.0}, 2.0, -2.0)
+	assert.EqualValues([]float64{2.0, -2.0, -2.0, 2.0}, res.RawData())
+}
+
+func TestPatternFromVec(t *testing.T) {
+	assert := assert.New(t)
+
+	v := mat.NewVecDense(4, []float64{0.5, -2.0, 0.0, 3.0})
+	p := PatternFromVec(v)
+	assert.Equal([]float64{1.0, -1.0, -1.0, 1.0}, p.RawData())
+	// vector is not modified
+	assert.Equal([]float64{0.5, -2.0, 0.0, 3.0}, v.RawVector().Data)
+
+	// bipolar vector round trips
+	v = mat.NewVecDense(4, []float64{1.0, -1.0, -1.0, 1.0})
+	p = PatternFromVec(v)
+	assert.True(mat.Equal(v, p.Vec()))
+
+	// strided vector
+	m := mat.NewDense(2, 2, []float64{1.0, -1.0, -1.0, 1.0})
+	p = PatternFromVec(m.ColView(1).(*mat.VecDense))
+	assert.Equal([]float64{-1.0, 1.0}, p.RawData())
+
+	assert.Nil(PatternFromVec(nil))
+	assert.Nil(PatternFromVec(&mat.VecDense{}))
+}
+
 func TestAddNoise(t *testing.T) {
 	assert := assert.New(t)
 
@@ -101,6 +388,192 @@ func TestAddNoise(t *testing.T) {
 	assert.NotEqual(p.v.RawVector().Data, np)
 }
 
+func TestOverlapMatrix(t *testing.T) {
+	assert := assert.New(t)
+
+	var patterns []*Pattern
+	errString := "invalid patterns supplied: %v"
+	overlaps, err := OverlapMatrix(patterns)
+	assert.Nil(overlaps)
+	assert.EqualError(err, fmt.Sprintf(errString, patterns))
+
+	patterns = []*Pattern{Encode([]float64{1.0, 1.0, -1.0, -1.0}), nil}
+	errString = "invalid pattern supplied: %v"
+	overlaps, err = OverlapMatrix(patterns)
+	assert.Nil(overlaps)
+	assert.EqualError(err, fmt.Sprintf(errString, patterns[1]))
+
+	patterns[1] = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %d"
+	overlaps, err = OverlapMatrix(patterns)
+	assert.Nil(overlaps)
+	assert.EqualError(err, fmt.Sprintf(errString, patterns[1].Len()))
+
+	patterns[1] = Encode([]float64{1.0, 1.0, 1.0, -1.0})
+	overlaps, err = OverlapMatrix(patterns)
+	assert.NoError(err)
+	expected := mat.NewSymDense(2, []float64{1.0, 0.5, 0.5, 1.0})
+	assert.True(mat.EqualApprox(expected, overlaps, 1e-9))
+}
+
+func TestSplitPatterns(t *testing.T) {
+	assert := assert.New(t)
+
+	var patterns []*Pattern
+	errString := "invalid patterns supplied: %v"
+	train, test, err := SplitPatterns(patterns, 0.5, nil)
+	assert.Nil(train)
+	assert.Nil(test)
+	assert.EqualError(err, fmt.Sprintf(errString, patterns))
+
+	patterns = randomPatterns(10, 5, rand.New(rand.NewSource(1)))
+	errString = "invalid train fraction: %f"
+	for _, frac := range []float64{0.0, 1.0, -0.5, 1.5} {
+		train, test, err = SplitPatterns(patterns, frac, nil)
+		assert.Nil(train)
+		assert.Nil(test)
+		assert.EqualError(err, fmt.Sprintf(errString, frac))
+	}
+
+	train, test, err = SplitPatterns(patterns, 0.7, rand.New(rand.NewSource(1)))
+	assert.NoError(err)
+	assert.Len(train, 7)
+	assert.Len(test, 3)
+
+	seen := make(map[*Pattern]bool)
+	for _, p := range append(train, test...) {
+		assert.False(seen[p])
+		seen[p] = true
+	}
+	assert.Len(seen, len(patterns))
+
+	// the same seed produces the same split
+	train2, test2, err := SplitPatterns(patterns, 0.7, rand.New(rand.NewSource(1)))
+	assert.NoError(err)
+	assert.Equal(train, train2)
+	assert.Equal(test, test2)
+}
+
+func TestMorph(t *testing.T) {
+	assert := assert.New(t)
+
+	a := Encode([]float64{1, 1, 1, 1, -1, -1, -1, -1})
+
+	var b *Pattern
+	errString := "invalid patterns supplied: %v, %v"
+	patterns, err := Morph(a, b, 5)
+	assert.Nil(patterns)
+	assert.EqualError(err, fmt.Sprintf(errString, a, b))
+
+	b = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %d"
+	patterns, err = Morph(a, b, 5)
+	assert.Nil(patterns)
+	assert.EqualError(err, fmt.Sprintf(errString, b.Len()))
+
+	b = Encode([]float64{-1, -1, -1, -1, 1, 1, 1, 1})
+	steps := 1
+	errString = "invalid number of steps: %d"
+	patterns, err = Morph(a, b, steps)
+	assert.Nil(patterns)
+	assert.EqualError(err, fmt.Sprintf(errString, steps))
+
+	steps = 5
+	patterns, err = Morph(a, b, steps)
+	assert.NoError(err)
+	assert.Len(patterns, steps)
+	assert.Equal(a.RawData(), patterns[0].RawData())
+	assert.Equal(b.RawData(), patterns[steps-1].RawData())
+	// every step moves closer to b
+	for k := range patterns {
+		assert.Equal(2*k, a.distance(patterns[k]))
+		assert.Equal(8-2*k, b.distance(patterns[k]))
+	}
+	// morphing does not modify the patterns
+	assert.Equal([]float64{1, 1, 1, 1, -1, -1, -1, -1}, a.RawData())
+}
+
+func TestInvert(t *testing.T) {
+	assert := assert.New(t)
+
+	p := Encode([]float64{1.0, 1.0, -1.0, 0.0})
+	ip := Invert(p)
+
+	assert.Equal([]float64{-1.0, -1.0, 1.0, 1.0}, ip.RawData())
+}
+
+func TestAugment(t *testing.T) {
+	assert := assert.New(t)
+
+	width, height := 4, 3
+	p := randomPatterns(1, width*height, rand.New(rand.NewSource(1)))[0]
+	orig := p.clone()
+
+	testCases := []struct {
+		maxShift int
+		count    int
+	}{
+		{0, 1},
+		{1, 9},
+		{2, 25},
+	}
+
+	for _, tc := range testCases {
+		patterns := Augment(p, width, height, tc.maxShift)
+		assert.Len(patterns, tc.count)
+		assert.Equal(orig.RawData(), patterns[0].RawData())
+		for _, a := range patterns {
+			assert.Equal(width*height, a.Len())
+		}
+	}
+
+	// shifted copies come from Shift
+	patterns := Augment(p, width, height, 1)
+	shifted, err := p.Shift(width, height, -1, -1)
+	assert.NoError(err)
+	assert.Equal(shifted.RawData(), patterns[1].RawData())
+	assert.Equal(orig.RawData(), p.RawData())
+
+	assert.Nil(Augment(p, width, height, -1))
+	assert.Nil(Augment(p, width, width, 1))
+}
+
+func TestTransposePattern(t *testing.T) {
+	assert := assert.New(t)
+
+	// 3x2 image with white pixels in its first column and top right corner
+	width, height := 3, 2
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	img.SetGray(0, 0, color.Gray{Y: 255})
+	img.SetGray(0, 1, color.Gray{Y: 255})
+	img.SetGray(2, 0, color.Gray{Y: 255})
+	rowMajor := Image2Pattern(img)
+	assert.Equal([]float64{1, -1, 1, 1, -1, -1}, rowMajor.RawData())
+
+	colMajor := Encode(make([]float64, width*height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			colMajor.RawData()[x*height+y] = rowMajor.At(y*width + x)
+		}
+	}
+	assert.Equal([]float64{1, 1, -1, -1, 1, -1}, colMajor.RawData())
+	assert.NotEqual(rowMajor.RawData(), colMajor.RawData())
+
+	errString := "invalid image dimensions: %dx%d"
+	res, err := TransposePattern(rowMajor, 2, 2)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, 2, 2))
+
+	// column-major pattern is a row-major pattern of the transposed image
+	res, err = TransposePattern(colMajor, height, width)
+	assert.NoError(err)
+	assert.Equal(rowMajor.RawData(), res.RawData())
+
+	res, err = TransposePattern(rowMajor, width, height)
+	assert.NoError(err)
+	assert.Equal(colMajor.RawData(), res.RawData())
+}
+
 func TestImage2Pattern(t *testing.T) {
 	assert := assert.New(t)
 
@@ -112,6 +585,109 @@ func TestImage2Pattern(t *testing.T) {
 	p := Encode([]float64{1.0, 1.0, 1.0, 1.0})
 
 	assert.Equal(imgP, p)
+
+	// fully transparent pixels are -1 regardless of their color
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	nrgba.Set(0, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	nrgba.Set(1, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 0})
+	nrgba.Set(0, 1, color.NRGBA{R: 200, G: 10, B: 90, A: 0})
+	nrgba.Set(1, 1, color.NRGBA{R: 255, G: 255, B: 255, A: 128})
+	imgP = Image2Pattern(nrgba)
+	assert.Equal([]float64{1.0, -1.0, -1.0, 1.0}, imgP.RawData())
+}
+
+func TestImage2PatternOtsu(t *testing.T) {
+	assert := assert.New(t)
+
+	// bimodal image of dark pixels around 40 and bright pixels around 180
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	expected := make([]float64, len(img.Pix))
+	maxDark := uint8(0)
+	for i := range img.Pix {
+		if rng.Intn(2) == 0 {
+			img.Pix[i] = uint8(30 + rng.Intn(21))
+			expected[i] = -1.0
+			if img.Pix[i] > maxDark {
+				maxDark = img.Pix[i]
+			}
+		} else {
+			img.Pix[i] = uint8(170 + rng.Intn(21))
+			expected[i] = 1.0
+		}
+	}
+	threshold := otsuThreshold(img.Pix)
+	// threshold separates the two modes
+	assert.True(threshold >= maxDark && threshold < 170)
+
+	p := Image2PatternOtsu(img)
+	assert.Equal(expected, p.RawData())
+
+	// fixed zero threshold encodes all the pixels to +1
+	p = Image2Pattern(img)
+	for i := 0; i < p.Len(); i++ {
+		assert.Equal(1.0, p.At(i))
+	}
+
+	// uniform image can't be split and falls back to zero threshold
+	img = image.NewGray(image.Rect(0, 0, 2, 2))
+	img.Pix = []byte{100, 100, 100, 100}
+	assert.Equal(uint8(0), otsuThreshold(img.Pix))
+	p = Image2PatternOtsu(img)
+	assert.Equal([]float64{1.0, 1.0, 1.0, 1.0}, p.RawData())
+}
+
+func TestDecodeImagePattern(t *testing.T) {
+	assert := assert.New(t)
+
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+	img.Pix = []byte{0, 100, 200, 255}
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	assert.NoError(err)
+
+	p, err := DecodeImagePattern(bytes.NewReader(buf.Bytes()), 0)
+	assert.NoError(err)
+	assert.Equal([]float64{-1.0, 1.0, 1.0, 1.0}, p.RawData())
+
+	p, err = DecodeImagePattern(bytes.NewReader(buf.Bytes()), 100)
+	assert.NoError(err)
+	assert.Equal([]float64{-1.0, -1.0, 1.0, 1.0}, p.RawData())
+
+	p, err = DecodeImagePattern(bytes.NewReader([]byte("foobar")), 0)
+	assert.Nil(p)
+	assert.Error(err)
+}
+
+func TestIsBinaryImage(t *testing.T) {
+	assert := assert.New(t)
+
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+	img.Pix = []byte{0, 255, 255, 0}
+	assert.True(IsBinaryImage(img, 0))
+	// binary image survives the round trip
+	assert.Equal(img, Pattern2Image(Image2Pattern(img), img.Bounds()))
+
+	gradient := image.NewGray(image.Rect(0, 0, 4, 1))
+	gradient.Pix = []byte{0, 85, 170, 255}
+	assert.False(IsBinaryImage(gradient, 0))
+	assert.False(IsBinaryImage(gradient, 80))
+	assert.True(IsBinaryImage(gradient, 85))
+	// tolerated grey pixels do not survive the round trip
+	assert.NotEqual(gradient, Pattern2Image(Image2Pattern(gradient), gradient.Bounds()))
+
+	// only pure black and white pixels are binary with zero threshold
+	nearly := image.NewGray(image.Rect(0, 0, 2, 1))
+	nearly.Pix = []byte{1, 255}
+	assert.False(IsBinaryImage(nearly, 0))
+	nearly.Pix = []byte{0, 254}
+	assert.False(IsBinaryImage(nearly, 0))
+	assert.True(IsBinaryImage(nearly, 1))
+
+	rgba := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	rgba.Set(0, 0, color.White)
+	rgba.Set(1, 0, color.RGBA{R: 128, A: 255})
+	assert.False(IsBinaryImage(rgba, 10))
 }
 
 func TestPattern2Image(t *testing.T) {
@@ -125,3 +701,129 @@ func TestPattern2Image(t *testing.T) {
 
 	assert.Equal(expImage, resImg)
 }
+
+func TestPattern2ImageColor(t *testing.T) {
+	assert := assert.New(t)
+
+	on := color.RGBA{R: 255, G: 200, A: 255}
+	off := color.RGBA{B: 128, A: 255}
+	p := Encode([]float64{1.0, -1.0, -1.0, 1.0, 1.0, -1.0})
+	r := image.Rect(1, 1, 4, 3)
+
+	img, err := Pattern2ImageColor(p, r, on, off)
+	assert.NoError(err)
+	assert.Equal(r, img.Bounds())
+	assert.Equal(on, img.At(1, 1))
+	assert.Equal(off, img.At(2, 1))
+	assert.Equal(on, img.At(1, 2))
+	assert.Equal(off, img.At(3, 2))
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	img, err = Pattern2ImageColor(pattern, r, on, off)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	errString = "invalid image dimensions: %dx%d"
+	img, err = Pattern2ImageColor(p, image.Rect(0, 0, 2, 2), on, off)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, 2, 2))
+}
+
+func TestToSquareImage(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 784
+	data := make([]float64, size)
+	for i := range data {
+		data[i] = float64(i%2*2 - 1)
+	}
+	p := Encode(data)
+	img, err := p.ToSquareImage()
+	assert.NoError(err)
+	assert.Equal(image.Rect(0, 0, 28, 28), img.Bounds())
+	assert.Equal(Pattern2Image(p, image.Rect(0, 0, 28, 28)), img)
+
+	p = Encode([]float64{1.0, -1.0, 1.0})
+	errString := "invalid pattern dimension: %d"
+	img, err = p.ToSquareImage()
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, p.Len()))
+}
+
+func TestDiffImage(t *testing.T) {
+	assert := assert.New(t)
+
+	a := Encode([]float64{1.0, 1.0, -1.0, -1.0})
+	r := image.Rect(0, 0, 2, 2)
+
+	var b *Pattern
+	errString := "invalid patterns supplied: %v, %v"
+	img, err := DiffImage(a, b, r)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, a, b))
+
+	b = Encode([]float64{1.0, 1.0})
+	errString = "invalid pattern dimension: %d"
+	img, err = DiffImage(a, b, r)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, b.Len()))
+
+	b = Encode([]float64{1.0, 1.0, 1.0, -1.0})
+	errString = "invalid image dimensions: %dx%d"
+	img, err = DiffImage(a, b, image.Rect(0, 0, 3, 3))
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, 3, 3))
+
+	img, err = DiffImage(a, b, r)
+	assert.NoError(err)
+	assert.Equal(r, img.Bounds())
+
+	black := color.RGBA{A: 255}
+	red := color.RGBA{R: 255, A: 255}
+	assert.Equal(black, img.At(0, 0))
+	assert.Equal(black, img.At(1, 0))
+	assert.Equal(red, img.At(0, 1))
+	assert.Equal(black, img.At(1, 1))
+}
+
+func TestRecallStrip(t *testing.T) {
+	assert := assert.New(t)
+
+	input := Encode([]float64{1.0, -1.0, -1.0, 1.0, 1.0, -1.0})
+	restored := Encode([]float64{1.0, -1.0, 1.0, 1.0, 1.0, -1.0})
+	r := image.Rect(0, 0, 3, 2)
+
+	img, err := RecallStrip(input, restored, r)
+	assert.NoError(err)
+	assert.Equal(3*r.Dx()+2, img.Bounds().Dx())
+	assert.Equal(r.Dy(), img.Bounds().Dy())
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	black := color.RGBA{A: 255}
+	gray := color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	// input tile
+	assert.Equal(white, img.At(0, 0))
+	assert.Equal(black, img.At(2, 0))
+	// separators
+	assert.Equal(gray, img.At(3, 0))
+	assert.Equal(gray, img.At(7, 1))
+	// restored tile
+	assert.Equal(white, img.At(6, 0))
+	// diff tile
+	assert.Equal(black, img.At(8, 0))
+	assert.Equal(color.RGBA{R: 255, A: 255}, img.At(10, 0))
+
+	var pattern *Pattern
+	img, err = RecallStrip(pattern, restored, r)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf("invalid patterns supplied: %v, %v", pattern, restored))
+
+	img, err = RecallStrip(input, Encode([]float64{1.0, -1.0}), r)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf("invalid pattern dimension: %d", 2))
+
+	img, err = RecallStrip(input, restored, image.Rect(0, 0, 2, 2))
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf("invalid image dimensions: %dx%d", 2, 2))
+}
@@ -0,0 +1,263 @@
+package hopfield
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// magic identifies files with saved networks
+	magic = "GOPF"
+	// version is the version of the saved networks format.
+	// Version 2 saves raw weights access and the state of network pseudorandom number generator.
+	version uint32 = 2
+)
+
+// network is gob encodable Hopfield network
+type network struct {
+	Size         int
+	Weights      []float64
+	Bias         []float64
+	External     []float64
+	Method       string
+	Memorised    int
+	TriState     bool
+	DeadBand     float64
+	On           float64
+	Off          float64
+	DivWindow    int
+	DivThreshold float64
+	Remember     bool
+	Remembered   [][]float64
+	Normalize    bool
+	MeanSubtract bool
+	Activity     []float64
+	RandomStart  bool
+	RawAccess    bool
+	RandState    []byte
+}
+
+// Save saves network in a file in path. Network is encoded using gob and prefixed by a header
+// with magic bytes and the version of the format, so incompatible files are detected by Load.
+// Raw weights access and the state of the network pseudorandom number generator are saved, too,
+// so the loaded network restores the patterns the same way as the saved one.
+// It returns error if the network fails to be encoded or written to path.
+func (n *Network) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := n.encode(f); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// Load loads network from a file in path and returns it.
+// Files saved without the header or in the earlier versions of the format are loaded, too.
+// It returns error if the file in path can't be read or if it does not contain a gob encoded network.
+// It returns error if the network was trained using unsupported training method.
+// If the file was saved in a newer version of the format, error wrapping ErrVersionMismatch is returned.
+func Load(path string) (*Network, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decode(f)
+}
+
+// UpdateOnDisk loads network from a file in path, stores supplied patterns in it and saves it back in path.
+// The file is updated atomically: the network is first saved in a temporary file which then replaces the original one.
+// The updated file keeps the permissions of the original file.
+// It returns error if the network fails to be loaded, if the patterns can not be stored or if the network fails to be saved.
+func UpdateOnDisk(path string, patterns []*Pattern) error {
+	n, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	if err := n.Store(patterns); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// temporary file replaces the original one, so it must keep its permissions
+	if err := f.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if err := n.encode(f); err != nil {
+		return err
+	}
+
+	// network must be on disk before the original file is replaced
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// encode encodes network into w using gob
+func (n *Network) encode(w io.Writer) error {
+	size := n.weights.Symmetric()
+	// copy upper triangular weights matrix row by row
+	weights := make([]float64, size*size)
+	raw := n.weights.RawSymmetric()
+	for i := 0; i < size; i++ {
+		copy(weights[i*size+i:(i+1)*size], raw.Data[i*raw.Stride+i:i*raw.Stride+size])
+	}
+
+	remembered := make([][]float64, len(n.remembered))
+	for i, p := range n.remembered {
+		remembered[i] = p.RawData()
+	}
+
+	header := make([]byte, len(magic)+4)
+	copy(header, magic)
+	binary.LittleEndian.PutUint32(header[len(magic):], version)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	return gob.NewEncoder(w).Encode(network{
+		Size:         size,
+		Weights:      weights,
+		Bias:         mat.Col(nil, 0, n.bias),
+		External:     mat.Col(nil, 0, n.external),
+		Method:       n.method,
+		Memorised:    n.memorised,
+		TriState:     n.triState,
+		DeadBand:     n.deadBand,
+		On:           n.on,
+		Off:          n.off,
+		DivWindow:    n.divWindow,
+		DivThreshold: n.divThreshold,
+		Remember:     n.remember,
+		Remembered:   remembered,
+		Normalize:    n.normalize,
+		MeanSubtract: n.meanSubtract,
+		Activity:     n.activity,
+		RandomStart:  n.randomStart,
+		RawAccess:    n.rawAccess,
+		RandState:    n.RandState(),
+	})
+}
+
+// decode decodes gob encoded network from r and returns it
+func decode(r io.Reader) (*Network, error) {
+	br := bufio.NewReader(r)
+	// networks saved without header are decoded as they are
+	if header, err := br.Peek(len(magic) + 4); err == nil && bytes.Equal(header[:len(magic)], []byte(magic)) {
+		// earlier versions of the format are decoded, too
+		if v := binary.LittleEndian.Uint32(header[len(magic):]); v == 0 || v > version {
+			return nil, fmt.Errorf("%w: %d", ErrVersionMismatch, v)
+		}
+		if _, err := br.Discard(len(header)); err != nil {
+			return nil, err
+		}
+	}
+	var net network
+	if err := gob.NewDecoder(br).Decode(&net); err != nil {
+		return nil, err
+	}
+
+	// network size must be positive
+	if net.Size <= 0 {
+		return nil, fmt.Errorf("invalid network size: %d", net.Size)
+	}
+	// network must be trained using supported method
+	if !supportedMethod(net.Method) {
+		return nil, fmt.Errorf("unsupported training method: %s", net.Method)
+	}
+	// weights and bias dimensions must match network size
+	if len(net.Weights) != net.Size*net.Size {
+		return nil, fmt.Errorf("invalid weights dimension: %d", len(net.Weights))
+	}
+	if len(net.Bias) != net.Size {
+		return nil, fmt.Errorf("invalid bias dimension: %d", len(net.Bias))
+	}
+	// networks saved before external field was configurable have zero external field
+	if net.External == nil {
+		net.External = make([]float64, net.Size)
+	}
+	if len(net.External) != net.Size {
+		return nil, fmt.Errorf("invalid external field dimension: %d", len(net.External))
+	}
+	// networks saved before neuron states were configurable use default states
+	if net.On == 0.0 && net.Off == 0.0 {
+		net.On, net.Off = 1.0, -1.0
+	}
+	// mean activity is kept only by the networks which stored patterns using covariance rule
+	if net.Activity != nil && len(net.Activity) != net.Size {
+		return nil, fmt.Errorf("invalid activity dimension: %d", len(net.Activity))
+	}
+	var remembered []*Pattern
+	for _, data := range net.Remembered {
+		// remembered patterns must have the same dimension as network size
+		if len(data) != net.Size {
+			return nil, fmt.Errorf("invalid pattern dimension: %d", len(data))
+		}
+		remembered = append(remembered, &Pattern{v: mat.NewVecDense(len(data), data)})
+	}
+	weights := mat.NewSymDense(net.Size, net.Weights)
+	bias := mat.NewVecDense(net.Size, net.Bias)
+	external := mat.NewVecDense(net.Size, net.External)
+
+	n := &Network{
+		weights:      weights,
+		bias:         bias,
+		external:     external,
+		method:       net.Method,
+		memorised:    net.Memorised,
+		triState:     net.TriState,
+		deadBand:     net.DeadBand,
+		on:           net.On,
+		off:          net.Off,
+		divWindow:    net.DivWindow,
+		divThreshold: net.DivThreshold,
+		remember:     net.Remember,
+		remembered:   remembered,
+		normalize:    net.Normalize,
+		meanSubtract: net.MeanSubtract,
+		activity:     net.Activity,
+		randomStart:  net.RandomStart,
+		rawAccess:    net.RawAccess,
+	}
+	// networks saved without their own pseudorandom number generator use default source
+	if net.RandState != nil {
+		if err := n.SetRandState(net.RandState); err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
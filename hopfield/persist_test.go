@@ -0,0 +1,178 @@
+package hopfield
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSaveLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "gopfield")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	size := 10
+	n, err := NewNetwork(size, "storkey", WithTriState(0.1), WithRemember(), WithSeed(1), WithRawAccess())
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	err = n.Store(randomPatterns(2, size, rand.New(rand.NewSource(1))))
+	assert.NoError(err)
+	err = n.SetExternalField([]float64{0.5, 0, 0, 0, 0, 0, 0, 0, 0, -0.5})
+	assert.NoError(err)
+
+	path := filepath.Join(dir, "network.gob")
+	err = n.Save(path)
+	assert.NoError(err)
+
+	loaded, err := Load(path)
+	assert.NoError(err)
+	// scratch buffers are not persisted
+	n.wBuf, n.hBuf = nil, nil
+	assert.Equal(n, loaded)
+	// loaded network draws the same randomness
+	noisy := n.addNoise(n.remembered[0].clone(), 30, rand.New(rand.NewSource(2)))
+	res, err := n.Restore(noisy.clone(), "async", 1)
+	assert.NoError(err)
+	loadedRes, err := loaded.Restore(noisy.clone(), "async", 1)
+	assert.NoError(err)
+	assert.Equal(res.RawData(), loadedRes.RawData())
+	assert.Equal(n.RandState(), loaded.RandState())
+
+	loaded, err = Load(filepath.Join(dir, "foobar"))
+	assert.Nil(loaded)
+	assert.Error(err)
+
+	err = os.WriteFile(path, []byte("foobar"), 0600)
+	assert.NoError(err)
+	loaded, err = Load(path)
+	assert.Nil(loaded)
+	assert.Error(err)
+}
+
+func TestUpdateOnDisk(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "gopfield")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	size := 10
+	patterns := randomPatterns(4, size, rand.New(rand.NewSource(1)))
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	path := filepath.Join(dir, "network.gob")
+	err = n.Save(path)
+	assert.NoError(err)
+	err = os.Chmod(path, 0644)
+	assert.NoError(err)
+
+	err = UpdateOnDisk(path, patterns[:2])
+	assert.NoError(err)
+	err = UpdateOnDisk(path, patterns[2:])
+	assert.NoError(err)
+
+	err = UpdateOnDisk(path, nil)
+	assert.Error(err)
+
+	loaded, err := Load(path)
+	assert.NoError(err)
+
+	err = n.Store(patterns)
+	assert.NoError(err)
+	assert.True(mat.EqualApprox(n.Weights(), loaded.Weights(), 1e-9))
+
+	// updated file keeps its permissions
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0644), info.Mode().Perm())
+
+	// no temporary files are left behind
+	files, err := os.ReadDir(dir)
+	assert.NoError(err)
+	assert.Len(files, 1)
+}
+
+func TestLoadVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "gopfield")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	size := 10
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store(randomPatterns(2, size, rand.New(rand.NewSource(1))))
+	assert.NoError(err)
+
+	path := filepath.Join(dir, "network.gob")
+	err = n.Save(path)
+	assert.NoError(err)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal([]byte(magic), data[:len(magic)])
+	assert.Equal(version, binary.LittleEndian.Uint32(data[len(magic):]))
+
+	// bump the version
+	binary.LittleEndian.PutUint32(data[len(magic):], version+1)
+	err = os.WriteFile(path, data, 0600)
+	assert.NoError(err)
+	loaded, err := Load(path)
+	assert.Nil(loaded)
+	assert.True(errors.Is(err, ErrVersionMismatch))
+
+	// networks saved in the earlier version of the format are still loaded
+	binary.LittleEndian.PutUint32(data[len(magic):], 1)
+	err = os.WriteFile(path, data, 0600)
+	assert.NoError(err)
+	loaded, err = Load(path)
+	assert.NoError(err)
+	assert.Equal(n.Memorised(), loaded.Memorised())
+
+	// networks saved without header are still loaded
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(network{
+		Size:      size,
+		Weights:   make([]float64, size*size),
+		Bias:      make([]float64, size),
+		Method:    "hebbian",
+		Memorised: 2,
+	})
+	assert.NoError(err)
+	err = os.WriteFile(path, buf.Bytes(), 0600)
+	assert.NoError(err)
+	loaded, err = Load(path)
+	assert.NoError(err)
+	assert.Equal(2, loaded.Memorised())
+
+	// networks trained using unsupported method are not loaded
+	buf.Reset()
+	err = gob.NewEncoder(&buf).Encode(network{
+		Size:    size,
+		Weights: make([]float64, size*size),
+		Bias:    make([]float64, size),
+		Method:  "foobar",
+	})
+	assert.NoError(err)
+	err = os.WriteFile(path, buf.Bytes(), 0600)
+	assert.NoError(err)
+	loaded, err = Load(path)
+	assert.Nil(loaded)
+	assert.EqualError(err, "unsupported training method: foobar")
+}
@@ -0,0 +1,57 @@
+package hopfield
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SolveQUBO minimizes the energy -0.5·sᵀQs over bipolar states s of +1/-1 values using Hopfield network
+// with weights Q and returns the lowest energy state found along with its energy.
+// Each of the restarts starts from a random state and runs async updates, which never increase the energy,
+// until no neuron changes its state or iters iterations are run. Diagonal of Q adds a constant -0.5·tr(Q)
+// to the energy of all the bipolar states, so it's ignored by the updates but included in the returned energy.
+// All randomness is drawn from rng. If rng is nil, default source is used. SolveQUBO does not modify Q.
+// It returns error if Q is nil or empty or if either iters or restarts is non-positive.
+func SolveQUBO(Q *mat.SymDense, iters, restarts int, rng *rand.Rand) (*Pattern, float64, error) {
+	// we need at least one neuron
+	if Q == nil || Q.IsEmpty() {
+		return nil, 0.0, fmt.Errorf("invalid QUBO matrix supplied: %v", Q)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, 0.0, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	// we need at least one restart
+	if restarts <= 0 {
+		return nil, 0.0, fmt.Errorf("invalid number of restarts: %d", restarts)
+	}
+	size := Q.Symmetric()
+	n, err := NewNetwork(size, "hebbian")
+	if err != nil {
+		return nil, 0.0, err
+	}
+	n.weights.CopySym(Q)
+	offset := 0.0
+	for i := 0; i < size; i++ {
+		offset -= 0.5 * Q.At(i, i)
+		n.weights.SetSym(i, i, 0.0)
+	}
+	var best *Pattern
+	bestEnergy := math.Inf(1)
+	for r := 0; r < restarts; r++ {
+		p := n.randomState(rng)
+		for i := 0; i < iters; i++ {
+			if !n.asyncSweep(p, rng) {
+				break
+			}
+		}
+		if energy := n.energy(p); energy < bestEnergy {
+			best, bestEnergy = p, energy
+		}
+	}
+
+	return best, bestEnergy + offset, nil
+}
@@ -0,0 +1,71 @@
+package hopfield
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+// quboEnergy computes QUBO energy -0.5·sᵀQs of state s
+func quboEnergy(Q *mat.SymDense, s []float64) float64 {
+	v := mat.NewVecDense(len(s), s)
+	return -0.5 * mat.Inner(v, Q, v)
+}
+
+func TestSolveQUBO(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 8
+	rng := rand.New(rand.NewSource(1))
+	Q := mat.NewSymDense(size, nil)
+	for i := 0; i < size; i++ {
+		for j := i; j < size; j++ {
+			Q.SetSym(i, j, rng.NormFloat64())
+		}
+	}
+	orig := mat.NewSymDense(size, nil)
+	orig.CopySym(Q)
+
+	var nilQ *mat.SymDense
+	errString := "invalid QUBO matrix supplied: %v"
+	p, energy, err := SolveQUBO(nilQ, 10, 10, rng)
+	assert.Nil(p)
+	assert.Equal(0.0, energy)
+	assert.EqualError(err, fmt.Sprintf(errString, nilQ))
+
+	errString = "invalid number of iterations: %d"
+	p, energy, err = SolveQUBO(Q, 0, 10, rng)
+	assert.Nil(p)
+	assert.Equal(0.0, energy)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	errString = "invalid number of restarts: %d"
+	p, energy, err = SolveQUBO(Q, 10, 0, rng)
+	assert.Nil(p)
+	assert.Equal(0.0, energy)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	// find the optimum by brute force
+	optimum := math.Inf(1)
+	s := make([]float64, size)
+	for bits := 0; bits < 1<<uint(size); bits++ {
+		for i := range s {
+			s[i] = -1.0
+			if bits&(1<<uint(i)) != 0 {
+				s[i] = 1.0
+			}
+		}
+		optimum = math.Min(optimum, quboEnergy(Q, s))
+	}
+
+	p, energy, err = SolveQUBO(Q, 10, 20, rng)
+	assert.NoError(err)
+	assert.Equal(size, p.Len())
+	assert.InDelta(optimum, energy, 1e-9)
+	assert.InDelta(quboEnergy(Q, p.RawData()), energy, 1e-9)
+	assert.True(mat.Equal(orig, Q))
+}
@@ -0,0 +1,86 @@
+package hopfield
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+)
+
+// source is a splitmix64 pseudorandom number source. Unlike the default source,
+// its whole state is a single integer, so it can be saved and restored.
+type source struct {
+	state uint64
+}
+
+// newSource creates new source seeded with seed and returns it
+func newSource(seed int64) *source {
+	s := &source{}
+	s.Seed(seed)
+
+	return s
+}
+
+// Seed seeds the source with seed
+func (s *source) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Uint64 returns pseudorandom 64-bit integer
+func (s *source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return z ^ (z >> 31)
+}
+
+// Int63 returns pseudorandom non-negative 63-bit integer
+func (s *source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// MarshalBinary encodes the source state and returns it
+func (s *source) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, s.state)
+
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the source state from data
+func (s *source) UnmarshalBinary(data []byte) error {
+	// state is a single 64-bit integer
+	if len(data) != 8 {
+		return fmt.Errorf("invalid random state length: %d", len(data))
+	}
+	s.state = binary.LittleEndian.Uint64(data)
+
+	return nil
+}
+
+// RandState returns the state of network pseudorandom number generator.
+// It returns nil if the network uses the default source, i.e. if it was not created using WithSeed option.
+func (n *Network) RandState() []byte {
+	if n.src == nil {
+		return nil
+	}
+	state, _ := n.src.MarshalBinary()
+
+	return state
+}
+
+// SetRandState sets the state of network pseudorandom number generator to state returned by RandState.
+// Restores which follow SetRandState draw the same randomness as the restores which followed RandState.
+// If the network uses the default source, SetRandState switches it to its own generator.
+// It returns error if state is invalid.
+func (n *Network) SetRandState(state []byte) error {
+	src := &source{}
+	if err := src.UnmarshalBinary(state); err != nil {
+		return err
+	}
+	n.src = src
+	n.rng = rand.New(src)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package hopfield
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandState(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	patterns := randomPatterns(3, size, rand.New(rand.NewSource(1)))
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.Nil(n.RandState())
+
+	state := []byte{1, 2, 3}
+	errString := "invalid random state length: %d"
+	err = n.SetRandState(state)
+	assert.EqualError(err, fmt.Sprintf(errString, len(state)))
+
+	n, err = NewNetwork(size, "hebbian", WithSeed(1))
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	// the same seed draws the same randomness
+	other, err := NewNetwork(size, "hebbian", WithSeed(1))
+	assert.NotNil(other)
+	assert.NoError(err)
+	assert.Equal(n.RandState(), other.RandState())
+
+	noisy := n.addNoise(patterns[0].clone(), 40, rand.New(rand.NewSource(2)))
+	state = n.RandState()
+	first := make([]*Pattern, 3)
+	for i := range first {
+		first[i], err = n.Restore(noisy.clone(), "async", 1)
+		assert.NoError(err)
+	}
+	assert.NotEqual(state, n.RandState())
+
+	err = n.SetRandState(state)
+	assert.NoError(err)
+	for i := range first {
+		res, err := n.Restore(noisy.clone(), "async", 1)
+		assert.NoError(err)
+		assert.Equal(first[i].RawData(), res.RawData())
+	}
+}
+
+func TestSource(t *testing.T) {
+	assert := assert.New(t)
+
+	src := newSource(1)
+	state, err := src.MarshalBinary()
+	assert.NoError(err)
+	vals := []int64{src.Int63(), src.Int63(), src.Int63()}
+	for _, v := range vals {
+		assert.True(v >= 0)
+	}
+
+	restored := &source{}
+	err = restored.UnmarshalBinary(state)
+	assert.NoError(err)
+	assert.Equal(vals, []int64{restored.Int63(), restored.Int63(), restored.Int63()})
+}
@@ -0,0 +1,515 @@
+package hopfield
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"math/rand"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// RecallRate measures how well the network recalls the supplied patterns from their noisy versions and returns it.
+// Each pattern is corrupted by pcnt percent of noise and then restored in async mode for iters iterations.
+// RecallRate returns the fraction of patterns which were restored exactly. Restores run in parallel in workers goroutines.
+// All randomness is drawn from rng upfront, so the result is the same regardless of the number of workers.
+// If rng is nil, default source is used. If progress is not nil, it is called with the number of patterns processed so far.
+// If invariant is true, a pattern restored to its inverse is counted as recalled, too.
+// It returns error if patterns is nil, if any of the patterns is invalid, or if either of noise, iters or workers is invalid.
+func (n *Network) RecallRate(patterns []*Pattern, pcnt, iters, workers int, invariant bool, rng *rand.Rand, progress func(done int)) (float64, error) {
+	recalled, err := n.recallCount(patterns, pcnt, iters, workers, invariant, rng, progress)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return float64(recalled) / float64(len(patterns)), nil
+}
+
+// recallCount counts the supplied patterns which are restored exactly from their noisy versions and returns it.
+// It accepts the same parameters and returns the same errors as RecallRate.
+func (n *Network) recallCount(patterns []*Pattern, pcnt, iters, workers int, invariant bool, rng *rand.Rand, progress func(done int)) (int, error) {
+	if err := n.checkPatterns(patterns); err != nil {
+		return 0, err
+	}
+	// noise is a percentage
+	if pcnt < 0 || pcnt > 100 {
+		return 0, fmt.Errorf("invalid noise percentage: %d", pcnt)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return 0, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	// we need at least one worker
+	if workers <= 0 {
+		return 0, fmt.Errorf("invalid number of workers: %d", workers)
+	}
+
+	// generate noisy inputs and restore seeds sequentially so the results don't depend on scheduling
+	inputs := make([]*Pattern, len(patterns))
+	seeds := make([]int64, len(patterns))
+	for i, p := range patterns {
+		inputs[i] = n.addNoise(p.clone(), pcnt, rng)
+		if rng == nil {
+			seeds[i] = rand.Int63()
+		} else {
+			seeds[i] = rng.Int63()
+		}
+	}
+
+	jobs := make(chan int)
+	results := make(chan bool)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res, _ := n.restoreAsync(inputs[i], iters, rand.New(rand.NewSource(seeds[i])))
+				d := res.distance(patterns[i])
+				results <- d == 0 || (invariant && d == res.Len())
+			}
+		}()
+	}
+	go func() {
+		for i := range inputs {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	done, recalled := 0, 0
+	for ok := range results {
+		if ok {
+			recalled++
+		}
+		done++
+		if progress != nil {
+			progress(done)
+		}
+	}
+
+	return recalled, nil
+}
+
+// PerfectRecallCount counts the supplied patterns which are recalled exactly from their noisy versions and returns it.
+// Each pattern is corrupted by noisePct percent of noise and then restored in async mode for iters iterations.
+// A pattern restored to its inverse is counted as recalled, too. The count at a fixed noise level summarizes
+// effective capacity of the network. All randomness is drawn from rng. If rng is nil, default source is used.
+// It returns the same errors as RecallRate.
+func (n *Network) PerfectRecallCount(patterns []*Pattern, noisePct, iters int, rng *rand.Rand) (int, error) {
+	return n.recallCount(patterns, noisePct, iters, 1, true, rng, nil)
+}
+
+// NoiseSweep measures recall rate of the supplied patterns at noise levels 0, step, 2*step, ... up to maxNoise
+// percent and returns the levels along with their recall rates. Recall rate at each level is measured
+// by RecallRate with the patterns restored in async mode for iters iterations.
+// All randomness is drawn from rng. If rng is nil, default source is used.
+// It returns error if patterns is nil, if any of the patterns is invalid, if maxNoise is not a valid
+// noise percentage or if either of step or iters is non-positive.
+func (n *Network) NoiseSweep(patterns []*Pattern, maxNoise, step, iters int, rng *rand.Rand) (levels []int, rates []float64, err error) {
+	// noise is a percentage
+	if maxNoise < 0 || maxNoise > 100 {
+		return nil, nil, fmt.Errorf("invalid noise percentage: %d", maxNoise)
+	}
+	// noise step must be a positive integer
+	if step <= 0 {
+		return nil, nil, fmt.Errorf("invalid noise step: %d", step)
+	}
+	for level := 0; level <= maxNoise; level += step {
+		rate, err := n.RecallRate(patterns, level, iters, 1, false, rng, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		levels = append(levels, level)
+		rates = append(rates, rate)
+	}
+
+	return levels, rates, nil
+}
+
+// SNR computes signal-to-noise ratio of all network neurons for mu-th of the supplied stored patterns and returns it.
+// Signal is the contribution of pattern mu to the local field of neuron i stored by Hebbian learning, ξᵘᵢ(N-1)/N.
+// Noise is the crosstalk of the other stored patterns: the local field hᵢ of neuron i computed from the network
+// weights for pattern mu less the signal. Contributions of the other patterns combine with their signs at each neuron,
+// so the standard deviation of the crosstalk of a neuron is the magnitude of hᵢ - ξᵘᵢ(N-1)/N.
+// Neurons with low SNR are the first to fail as the network load grows. Neurons without crosstalk have +Inf SNR.
+// It returns error if patterns is nil, if any of the patterns is invalid or if mu is not a valid pattern index.
+func (n *Network) SNR(patterns []*Pattern, mu int) ([]float64, error) {
+	if err := n.checkPatterns(patterns); err != nil {
+		return nil, err
+	}
+	// mu must index the supplied patterns
+	if mu < 0 || mu >= len(patterns) {
+		return nil, fmt.Errorf("invalid pattern index: %d", mu)
+	}
+	p := patterns[mu]
+	dim := p.Len()
+	signal := float64(dim-1) / float64(dim)
+	// h stores local fields of all neurons computed from the network weights
+	h := mat.NewVecDense(dim, nil)
+	h.MulVec(n.weights, p.Vec())
+	snr := make([]float64, dim)
+	for i := range snr {
+		crosstalk := h.AtVec(i) - p.At(i)*signal
+		if crosstalk == 0.0 {
+			snr[i] = math.Inf(1)
+			continue
+		}
+		snr[i] = signal / math.Abs(crosstalk)
+	}
+
+	return snr, nil
+}
+
+// OrderParameterEA estimates Edwards-Anderson order parameter q = (1/N) Σ ⟨sᵢ⟩² of the network and returns it.
+// Mean states ⟨sᵢ⟩ of the neurons are averaged over trials restorations which start from random states
+// and run in async mode for iters iterations. All randomness is drawn from rng. If rng is nil, default source is used.
+// Order parameter close to 1 indicates the network settles in a single attractor, while order parameter
+// close to 0 indicates many attractors, e.g. a pattern and its inverse or a glassy phase.
+// It returns error if either trials or iters is non-positive.
+func (n *Network) OrderParameterEA(trials, iters int, rng *rand.Rand) (float64, error) {
+	// we need at least one trial
+	if trials <= 0 {
+		return 0.0, fmt.Errorf("invalid number of trials: %d", trials)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return 0.0, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	_, nCount := n.weights.Dims()
+	// mean accumulates the mean states of neurons
+	mean := make([]float64, nCount)
+	for t := 0; t < trials; t++ {
+		res, _ := n.restoreAsync(n.randomState(rng), iters, rng)
+		for i := range mean {
+			mean[i] += res.At(i) / float64(trials)
+		}
+	}
+	q := 0.0
+	for _, m := range mean {
+		q += m * m
+	}
+
+	return q / float64(nCount), nil
+}
+
+// SpuriousRate estimates the fraction of random network states which converge to spurious attractors and returns it.
+// Each of trials restorations starts from a random state and runs in async mode for iters iterations.
+// Restored state is spurious if it is neither any of the stored patterns nor its inverse.
+// All randomness is drawn from rng. If rng is nil, default source is used. High rate warns of network overload.
+// It returns error if stored is nil, if any of the stored patterns is invalid or if either trials or iters is non-positive.
+func (n *Network) SpuriousRate(stored []*Pattern, trials, iters int, rng *rand.Rand) (float64, error) {
+	if err := n.checkPatterns(stored); err != nil {
+		return 0.0, err
+	}
+	// we need at least one trial
+	if trials <= 0 {
+		return 0.0, fmt.Errorf("invalid number of trials: %d", trials)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return 0.0, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	spurious := 0
+	for t := 0; t < trials; t++ {
+		res, _ := n.restoreAsync(n.randomState(rng), iters, rng)
+		found := false
+		for _, p := range stored {
+			if d := res.distance(p); d == 0 || d == res.Len() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			spurious++
+		}
+	}
+
+	return float64(spurious) / float64(trials), nil
+}
+
+// ConvergenceStats restores trials random network states in async mode and returns the mean and the maximum number
+// of sweeps the restores needed to converge along with their histogram: histogram[k] is the number of restores which
+// converged after k sweeps changing the state. Restore converges when a sweep changes no neuron, so the state
+// must stop changing within maxIters sweeps. Restores which do not converge are left out of the statistics,
+// so the histogram sums to the number of converged restores. If no restore converges, mean and max are zero.
+// All randomness is drawn from rng. If rng is nil, default source is used.
+// It returns error if either trials or maxIters is non-positive.
+func (n *Network) ConvergenceStats(trials, maxIters int, rng *rand.Rand) (mean, max float64, histogram []int, err error) {
+	// we need at least one trial
+	if trials <= 0 {
+		return 0.0, 0.0, nil, fmt.Errorf("invalid number of trials: %d", trials)
+	}
+	// number of max iterations must be a positive integer
+	if maxIters <= 0 {
+		return 0.0, 0.0, nil, fmt.Errorf("invalid number of iterations: %d", maxIters)
+	}
+	histogram = make([]int, maxIters)
+	converged := 0
+	for t := 0; t < trials; t++ {
+		p := n.randomState(rng)
+		for sweeps := 0; sweeps < maxIters; sweeps++ {
+			if n.asyncSweep(p, rng) {
+				continue
+			}
+			histogram[sweeps]++
+			mean += float64(sweeps)
+			max = math.Max(max, float64(sweeps))
+			converged++
+			break
+		}
+	}
+	if converged > 0 {
+		mean /= float64(converged)
+	}
+
+	return mean, max, histogram, nil
+}
+
+// MeanBasinRadius estimates basin radius of each of the supplied patterns and returns their mean.
+// Basin radius of a pattern is the largest fraction of its neurons which can be flipped such that
+// all of trials restorations, each running in async mode for iters iterations, recall the pattern.
+// Radius of a pattern which is not a fixed point of the network is 0, the largest radius is 0.5.
+// All randomness is drawn from rng. If rng is nil, default source is used.
+// It returns error if patterns is nil, if any of the patterns is invalid or if either trials or iters is non-positive.
+func (n *Network) MeanBasinRadius(patterns []*Pattern, trials, iters int, rng *rand.Rand) (float64, error) {
+	if err := n.checkPatterns(patterns); err != nil {
+		return 0.0, err
+	}
+	// we need at least one trial
+	if trials <= 0 {
+		return 0.0, fmt.Errorf("invalid number of trials: %d", trials)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return 0.0, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	sum := 0.0
+	for _, p := range patterns {
+		sum += float64(n.basinRadius(p, trials, iters, rng)) / float64(p.Len())
+	}
+
+	return sum / float64(len(patterns)), nil
+}
+
+// basinRadius returns the largest number of neurons of pattern p which can be flipped such that
+// all of trials restorations running in async mode for iters iterations recall p
+func (n *Network) basinRadius(p *Pattern, trials, iters int, rng *rand.Rand) int {
+	radius := 0
+	// flipping more than half of the neurons gets closer to the inverse of the pattern
+	for flips := 0; flips <= p.Len()/2; flips++ {
+		for t := 0; t < trials; t++ {
+			noisy := p.clone()
+			for _, i := range perm(rng, p.Len())[:flips] {
+				noisy.RawData()[i] = n.flip(noisy.At(i))
+			}
+			if res, _ := n.restoreAsync(noisy, iters, rng); !res.equal(p) {
+				return radius
+			}
+		}
+		radius = flips
+	}
+
+	return radius
+}
+
+// AttractorCount estimates the number of distinct attractors of the network and returns it.
+// Each of trials restorations starts from a random state and runs in async mode until the state stops
+// changing or iters iterations are run. AttractorCount returns the number of distinct converged states;
+// restorations which do not converge are ignored. Pattern and its inverse are counted as distinct attractors.
+// All randomness is drawn from rng. If rng is nil, default source is used. Growing count warns of spurious attractors.
+// It returns error if either trials or iters is non-positive.
+func (n *Network) AttractorCount(trials, iters int, rng *rand.Rand) (int, error) {
+	// we need at least one trial
+	if trials <= 0 {
+		return 0, fmt.Errorf("invalid number of trials: %d", trials)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return 0, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	// seen indexes the distinct attractors by their hash
+	seen := make(map[uint64][]*Pattern)
+	count := 0
+	for t := 0; t < trials; t++ {
+		p := n.randomState(rng)
+		converged := false
+		for i := 0; i < iters && !converged; i++ {
+			converged = !n.asyncSweep(p, rng)
+		}
+		if !converged {
+			continue
+		}
+		duplicate := false
+		for _, s := range seen[p.Hash()] {
+			if s.equal(p) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			seen[p.Hash()] = append(seen[p.Hash()], p)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// randomState generates pseudorandom network state using rng and returns it.
+// If rng is nil, default source is used.
+func (n *Network) randomState(rng *rand.Rand) *Pattern {
+	_, nCount := n.weights.Dims()
+	data := make([]float64, nCount)
+	for i := range data {
+		data[i] = n.off
+		if intn(rng, 2) == 1 {
+			data[i] = n.on
+		}
+	}
+
+	return &Pattern{v: mat.NewVecDense(nCount, data)}
+}
+
+// CriticalErrorRate returns the single-bit error probability of recall from Hebbian network loaded by alpha
+// patterns per neuron predicted by signal-to-noise analysis: 0.5·erfc(1/√(2α)). The prediction holds for
+// the first update of a stored pattern; empirical error rates can be compared to it using BitErrorRate.
+// Network without any stored patterns makes no errors, so non-positive alpha yields zero error rate.
+func CriticalErrorRate(alpha float64) float64 {
+	if alpha <= 0.0 {
+		return 0.0
+	}
+
+	return 0.5 * math.Erfc(1/math.Sqrt(2*alpha))
+}
+
+// BitErrorRate computes the fraction of values of recalled patterns which differ from the values of stored patterns and returns it.
+// Patterns are compared pairwise: i-th recalled pattern is compared to i-th stored pattern.
+// If invariant is true, each recalled pattern is compared to the closer of the stored pattern and its inverse.
+// It returns error if either of the pattern sets is nil, if they have different sizes or if any of the patterns is invalid.
+func BitErrorRate(stored, recalled []*Pattern, invariant bool) (float64, error) {
+	if err := checkRecalled(stored, recalled); err != nil {
+		return 0.0, err
+	}
+	errs, total := 0, 0
+	for i := range stored {
+		d := recalled[i].distance(stored[i])
+		// inverse of the stored pattern is at the complementary distance
+		if invariant && recalled[i].Len()-d < d {
+			d = recalled[i].Len() - d
+		}
+		errs += d
+		total += stored[i].Len()
+	}
+
+	return float64(errs) / float64(total), nil
+}
+
+// MutualInformation computes mutual information in bits between the values of stored patterns and the values
+// of recalled patterns and returns it. Patterns are compared pairwise: i-th recalled pattern is compared to i-th
+// stored pattern and the joint distribution of the stored and recalled bits is estimated over all their values.
+// Positive values are considered to be set bits. Perfect recall of patterns with balanced bits yields 1 bit,
+// recall independent of the stored patterns yields 0 bits.
+// It returns error if either of the pattern sets is nil, if they have different sizes or if any of the patterns is invalid.
+func MutualInformation(stored, recalled []*Pattern) (float64, error) {
+	if err := checkRecalled(stored, recalled); err != nil {
+		return 0.0, err
+	}
+	// joint counts the pairs of stored and recalled bits
+	var joint [2][2]float64
+	total := 0.0
+	for i := range stored {
+		for j := 0; j < stored[i].Len(); j++ {
+			joint[bit(stored[i].At(j))][bit(recalled[i].At(j))]++
+			total++
+		}
+	}
+	mi := 0.0
+	for s := 0; s < 2; s++ {
+		for r := 0; r < 2; r++ {
+			if joint[s][r] == 0.0 {
+				continue
+			}
+			pJoint := joint[s][r] / total
+			pStored := (joint[s][0] + joint[s][1]) / total
+			pRecalled := (joint[0][r] + joint[1][r]) / total
+			mi += pJoint * math.Log2(pJoint/(pStored*pRecalled))
+		}
+	}
+
+	return mi, nil
+}
+
+// bit returns 1 if val is positive, otherwise it returns 0
+func bit(val float64) int {
+	if val > 0.0 {
+		return 1
+	}
+
+	return 0
+}
+
+// checkRecalled returns error if either of stored and recalled pattern sets is nil, if they have different sizes,
+// if any of the patterns is nil or if any recalled pattern does not have the same dimension as its stored pattern
+func checkRecalled(stored, recalled []*Pattern) error {
+	// patterns can't be nil
+	if len(stored) == 0 {
+		return fmt.Errorf("invalid patterns supplied: %v", stored)
+	}
+	// every stored pattern must have its recalled pattern
+	if len(stored) != len(recalled) {
+		return fmt.Errorf("invalid number of recalled patterns: %d", len(recalled))
+	}
+	for i := range stored {
+		// nil patterns are invalid
+		if stored[i] == nil || recalled[i] == nil {
+			return fmt.Errorf("invalid pattern supplied: %d", i)
+		}
+		// patterns must have the same dimension
+		if stored[i].Len() != recalled[i].Len() {
+			return fmt.Errorf("invalid pattern dimension: %d", recalled[i].Len())
+		}
+	}
+
+	return nil
+}
+
+// BasinMontage renders a montage of the supplied patterns, their noisy versions and their restorations and returns it.
+// Every pattern is rendered in its own row of three r sized images: the original pattern, the pattern corrupted
+// by noisePct percent of noise and the noisy pattern restored by network n in async mode for iters iterations.
+// BasinMontage does not modify the supplied patterns.
+// It returns error if patterns is nil, if any of the patterns is invalid, if r does not have as many pixels
+// as there are network neurons or if either of noise or iters is invalid.
+func BasinMontage(n *Network, patterns []*Pattern, r image.Rectangle, noisePct, iters int) (image.Image, error) {
+	if err := n.checkPatterns(patterns); err != nil {
+		return nil, err
+	}
+	// every neuron must have its pixel
+	if r.Dx()*r.Dy() != patterns[0].Len() {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", r.Dx(), r.Dy())
+	}
+	// noise is a percentage
+	if noisePct < 0 || noisePct > 100 {
+		return nil, fmt.Errorf("invalid noise percentage: %d", noisePct)
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	w, h := r.Dx(), r.Dy()
+	img := image.NewGray(image.Rect(0, 0, 3*w, len(patterns)*h))
+	for i, p := range patterns {
+		noisy := n.addNoise(p.clone(), noisePct, nil)
+		restored, _ := n.restoreAsync(noisy.clone(), iters, nil)
+		for j, col := range []*Pattern{p, noisy, restored} {
+			tile := Pattern2Image(col, image.Rect(0, 0, w, h))
+			draw.Draw(img, image.Rect(j*w, i*h, (j+1)*w, (i+1)*h), tile, image.Point{}, draw.Src)
+		}
+	}
+
+	return img, nil
+}
@@ -0,0 +1,659 @@
+package hopfield
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomPatterns generates count random patterns of size length using rng
+func randomPatterns(count, size int, rng *rand.Rand) []*Pattern {
+	patterns := make([]*Pattern, count)
+	for i := range patterns {
+		data := make([]float64, size)
+		for j := range data {
+			data[j] = rng.NormFloat64()
+		}
+		patterns[i] = Encode(data)
+	}
+
+	return patterns
+}
+
+func TestRecallRate(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	patterns := randomPatterns(8, size, rand.New(rand.NewSource(1)))
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var nilPatterns []*Pattern
+	errString := "invalid patterns supplied: %v"
+	rate, err := n.RecallRate(nilPatterns, 10, 5, 1, false, nil, nil)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, nilPatterns))
+
+	invalid := []*Pattern{Encode([]float64{1.0, -1.0})}
+	errString = "invalid pattern dimension: %d"
+	rate, err = n.RecallRate(invalid, 10, 5, 1, false, nil, nil)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, invalid[0].Len()))
+
+	pcnt := 101
+	errString = "invalid noise percentage: %d"
+	rate, err = n.RecallRate(patterns, pcnt, 5, 1, false, nil, nil)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, pcnt))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	rate, err = n.RecallRate(patterns, 10, iters, 1, false, nil, nil)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	workers := 0
+	errString = "invalid number of workers: %d"
+	rate, err = n.RecallRate(patterns, 10, 5, workers, false, nil, nil)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, workers))
+
+	var calls []int
+	progress := func(done int) { calls = append(calls, done) }
+	seqRate, err := n.RecallRate(patterns, 20, 5, 1, false, rand.New(rand.NewSource(42)), progress)
+	assert.NoError(err)
+	assert.Equal([]int{1, 2, 3, 4, 5, 6, 7, 8}, calls)
+
+	for _, workers := range []int{2, 4, 16} {
+		rate, err = n.RecallRate(patterns, 20, 5, workers, false, rand.New(rand.NewSource(42)), nil)
+		assert.NoError(err)
+		assert.Equal(seqRate, rate)
+	}
+	// stored patterns are stable, so they are all recalled without noise
+	rate, err = n.RecallRate(patterns, 0, 5, 2, false, rand.New(rand.NewSource(42)), nil)
+	assert.NoError(err)
+	assert.Equal(1.0, rate)
+	// full noise inverts the patterns
+	rate, err = n.RecallRate(patterns, 100, 5, 2, false, rand.New(rand.NewSource(42)), nil)
+	assert.NoError(err)
+	assert.Equal(0.0, rate)
+	rate, err = n.RecallRate(patterns, 100, 5, 2, true, rand.New(rand.NewSource(42)), nil)
+	assert.NoError(err)
+	assert.Equal(1.0, rate)
+	// patterns are not modified
+	stable, err := n.StableCount(patterns)
+	assert.NoError(err)
+	assert.Equal(len(patterns), stable)
+}
+
+func TestRecallRateInvariant(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 25
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	stored := randomPatterns(1, size, rand.New(rand.NewSource(1)))
+	err = n.Store(stored)
+	assert.NoError(err)
+
+	// heavy noise pushes most inputs into the basin of the inverted pattern
+	patterns := []*Pattern{stored[0], stored[0], stored[0], stored[0], stored[0], stored[0]}
+	rate, err := n.RecallRate(patterns, 90, 10, 2, false, rand.New(rand.NewSource(42)), nil)
+	assert.NoError(err)
+	assert.True(rate < 1.0)
+
+	rate, err = n.RecallRate(patterns, 90, 10, 2, true, rand.New(rand.NewSource(42)), nil)
+	assert.NoError(err)
+	assert.Equal(1.0, rate)
+}
+
+func TestBitErrorRate(t *testing.T) {
+	assert := assert.New(t)
+
+	var stored []*Pattern
+	errString := "invalid patterns supplied: %v"
+	rate, err := BitErrorRate(stored, nil, false)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, stored))
+
+	stored = []*Pattern{Encode([]float64{1.0, 1.0, -1.0, -1.0}), Encode([]float64{1.0, -1.0, 1.0, -1.0})}
+	recalled := []*Pattern{Encode([]float64{1.0, 1.0, -1.0, 1.0})}
+	errString = "invalid number of recalled patterns: %d"
+	rate, err = BitErrorRate(stored, recalled, false)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, len(recalled)))
+
+	recalled = append(recalled, nil)
+	errString = "invalid pattern supplied: %d"
+	rate, err = BitErrorRate(stored, recalled, false)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, 1))
+
+	recalled[1] = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %d"
+	rate, err = BitErrorRate(stored, recalled, false)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, recalled[1].Len()))
+
+	// second pattern is recalled perfectly inverted
+	recalled[1] = Invert(Encode([]float64{1.0, -1.0, 1.0, -1.0}))
+	rate, err = BitErrorRate(stored, recalled, false)
+	assert.NoError(err)
+	assert.InDelta(5.0/8.0, rate, 0.0001)
+
+	rate, err = BitErrorRate(stored, recalled, true)
+	assert.NoError(err)
+	assert.InDelta(1.0/8.0, rate, 0.0001)
+}
+
+func TestPerfectRecallCount(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(30, size, rng)
+
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	count, err := n.PerfectRecallCount(nil, 10, 10, rng)
+	assert.Equal(0, count)
+	assert.Error(err)
+
+	errString := "invalid noise percentage: %d"
+	count, err = n.PerfectRecallCount(patterns, 101, 10, rng)
+	assert.Equal(0, count)
+	assert.EqualError(err, fmt.Sprintf(errString, 101))
+
+	err = n.Store(patterns[:5])
+	assert.NoError(err)
+	few, err := n.PerfectRecallCount(patterns[:5], 10, 10, rng)
+	assert.NoError(err)
+	assert.Equal(5, few)
+
+	// overloaded network recalls fewer patterns
+	err = n.Store(patterns[5:])
+	assert.NoError(err)
+	many, err := n.PerfectRecallCount(patterns, 10, 10, rng)
+	assert.NoError(err)
+	assert.True(many < few)
+
+	// without noise only the stable patterns are recalled
+	stable, err := n.StableCount(patterns)
+	assert.NoError(err)
+	count, err = n.PerfectRecallCount(patterns, 0, 10, rng)
+	assert.NoError(err)
+	assert.Equal(stable, count)
+}
+
+func TestCriticalErrorRate(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		alpha    float64
+		expected float64
+	}{
+		{-1.0, 0.0},
+		{0.0, 0.0},
+		{0.1, 0.00078},
+		{0.138, 0.00355},
+		{0.5, 0.07865},
+		{1.0, 0.15866},
+	}
+
+	for _, tc := range testCases {
+		assert.InDelta(tc.expected, CriticalErrorRate(tc.alpha), 1e-5)
+	}
+}
+
+func TestNoiseSweep(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	maxNoise := 101
+	errString := "invalid noise percentage: %d"
+	levels, rates, err := n.NoiseSweep(patterns, maxNoise, 10, 10, rng)
+	assert.Nil(levels)
+	assert.Nil(rates)
+	assert.EqualError(err, fmt.Sprintf(errString, maxNoise))
+
+	step := 0
+	errString = "invalid noise step: %d"
+	levels, rates, err = n.NoiseSweep(patterns, 50, step, 10, rng)
+	assert.Nil(levels)
+	assert.Nil(rates)
+	assert.EqualError(err, fmt.Sprintf(errString, step))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	levels, rates, err = n.NoiseSweep(patterns, 50, 10, iters, rng)
+	assert.Nil(levels)
+	assert.Nil(rates)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	// repeated patterns average out the randomness of single restores
+	var repeated []*Pattern
+	for i := 0; i < 40; i++ {
+		repeated = append(repeated, patterns...)
+	}
+	levels, rates, err = n.NoiseSweep(repeated, 100, 20, 10, rng)
+	assert.NoError(err)
+	assert.Equal([]int{0, 20, 40, 60, 80, 100}, levels)
+	assert.Len(rates, len(levels))
+	assert.Equal(1.0, rates[0])
+	for i := 1; i < len(rates); i++ {
+		assert.True(rates[i] <= rates[i-1])
+	}
+	assert.True(rates[len(rates)-1] < 1.0)
+
+	// inputs are not corrupted at zero noise level, so the stable stored patterns
+	// are recalled even if the network is overloaded and their basins are tiny
+	size = 50
+	n, err = NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	patterns = randomPatterns(16, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+	var stable []*Pattern
+	for _, p := range patterns {
+		ok, err := n.Stable(p)
+		assert.NoError(err)
+		if ok {
+			for i := 0; i < 20; i++ {
+				stable = append(stable, p)
+			}
+		}
+	}
+	assert.NotEmpty(stable)
+	levels, rates, err = n.NoiseSweep(stable, 0, 10, 10, rng)
+	assert.NoError(err)
+	assert.Equal([]int{0}, levels)
+	assert.Equal([]float64{1.0}, rates)
+}
+
+func TestSNR(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	patterns := []*Pattern{
+		Encode([]float64{1, 1, 1, 1}),
+		Encode([]float64{1, 1, 1, -1}),
+	}
+
+	var invalid []*Pattern
+	errString := "invalid patterns supplied: %v"
+	snr, err := n.SNR(invalid, 0)
+	assert.Nil(snr)
+	assert.EqualError(err, fmt.Sprintf(errString, invalid))
+
+	mu := 2
+	errString = "invalid pattern index: %d"
+	snr, err = n.SNR(patterns, mu)
+	assert.Nil(snr)
+	assert.EqualError(err, fmt.Sprintf(errString, mu))
+
+	// single pattern has no crosstalk
+	err = n.Store(patterns[:1])
+	assert.NoError(err)
+	snr, err = n.SNR(patterns[:1], 0)
+	assert.NoError(err)
+	for _, s := range snr {
+		assert.True(math.IsInf(s, 1))
+	}
+
+	// signal is 3/4; crosstalk of the first three neurons is 1/4 and of the last one -3/4
+	err = n.Store(patterns[1:])
+	assert.NoError(err)
+	snr, err = n.SNR(patterns, 0)
+	assert.NoError(err)
+	assert.InDeltaSlice([]float64{3, 3, 3, 1}, snr, 1e-9)
+
+	// crosstalk of the inverted first pattern is 3/4 at every neuron, so it
+	// adds up with the crosstalk of the first three neurons and cancels out at the last one
+	patterns = append(patterns, Invert(patterns[0].clone()))
+	err = n.Store(patterns[2:])
+	assert.NoError(err)
+	snr, err = n.SNR(patterns, 0)
+	assert.NoError(err)
+	assert.InDeltaSlice([]float64{0.75, 0.75, 0.75}, snr[:3], 1e-9)
+	assert.True(math.IsInf(snr[3], 1))
+}
+
+func TestOrderParameterEA(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	trials := 0
+	errString := "invalid number of trials: %d"
+	q, err := n.OrderParameterEA(trials, 10, rng)
+	assert.Equal(0.0, q)
+	assert.EqualError(err, fmt.Sprintf(errString, trials))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	q, err = n.OrderParameterEA(10, iters, rng)
+	assert.Equal(0.0, q)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	pattern := randomPatterns(1, size, rng)[0]
+	err = n.Store([]*Pattern{pattern})
+	assert.NoError(err)
+
+	// pattern and its inverse are equally likely attractors
+	q, err = n.OrderParameterEA(200, 10, rng)
+	assert.NoError(err)
+	assert.True(q < 0.1)
+
+	// external field aligned with the pattern leaves it the only attractor
+	err = n.SetExternalField(pattern.RawData())
+	assert.NoError(err)
+	q, err = n.OrderParameterEA(200, 10, rng)
+	assert.NoError(err)
+	assert.InDelta(1.0, q, 1e-9)
+}
+
+func TestSpuriousRate(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(30, size, rng)
+
+	var invalid []*Pattern
+	errString := "invalid patterns supplied: %v"
+	rate, err := n.SpuriousRate(invalid, 10, 10, rng)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, invalid))
+
+	trials := 0
+	errString = "invalid number of trials: %d"
+	rate, err = n.SpuriousRate(patterns, trials, 10, rng)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, trials))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	rate, err = n.SpuriousRate(patterns, 10, iters, rng)
+	assert.Equal(0.0, rate)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	// lightly loaded network
+	err = n.Store(patterns[:2])
+	assert.NoError(err)
+	light, err := n.SpuriousRate(patterns[:2], 100, 10, rng)
+	assert.NoError(err)
+
+	// overloaded network
+	err = n.Store(patterns[2:])
+	assert.NoError(err)
+	overloaded, err := n.SpuriousRate(patterns, 100, 10, rng)
+	assert.NoError(err)
+
+	assert.True(light < overloaded)
+	assert.True(overloaded > 0.5)
+}
+
+func TestConvergenceStats(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	err = n.Store(randomPatterns(3, size, rng))
+	assert.NoError(err)
+
+	errString := "invalid number of trials: %d"
+	mean, max, histogram, err := n.ConvergenceStats(0, 10, rng)
+	assert.Equal(0.0, mean)
+	assert.Equal(0.0, max)
+	assert.Nil(histogram)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	errString = "invalid number of iterations: %d"
+	mean, max, histogram, err = n.ConvergenceStats(10, 0, rng)
+	assert.Equal(0.0, mean)
+	assert.Equal(0.0, max)
+	assert.Nil(histogram)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	trials := 50
+	mean, max, histogram, err = n.ConvergenceStats(trials, 20, rng)
+	assert.NoError(err)
+	assert.Len(histogram, 20)
+	converged, sum := 0, 0.0
+	for k, count := range histogram {
+		converged += count
+		sum += float64(k * count)
+		if count > 0 {
+			assert.True(float64(k) <= max)
+		}
+	}
+	// async restores of a lightly loaded network always converge
+	assert.Equal(trials, converged)
+	assert.InDelta(sum/float64(converged), mean, 1e-9)
+	assert.True(mean > 0.0 && mean <= max)
+
+	// restores which need more sweeps than allowed are left out
+	_, _, histogram, err = n.ConvergenceStats(trials, 1, rng)
+	assert.NoError(err)
+	assert.Len(histogram, 1)
+	assert.True(histogram[0] < trials)
+}
+
+func TestMeanBasinRadius(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(2, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	radius, err := n.MeanBasinRadius(nil, 5, 10, rng)
+	assert.Equal(0.0, radius)
+	assert.Error(err)
+
+	errString := "invalid number of trials: %d"
+	radius, err = n.MeanBasinRadius(patterns, 0, 10, rng)
+	assert.Equal(0.0, radius)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	errString = "invalid number of iterations: %d"
+	radius, err = n.MeanBasinRadius(patterns, 5, 0, rng)
+	assert.Equal(0.0, radius)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	small, err := n.MeanBasinRadius(patterns, 5, 10, rng)
+	assert.NoError(err)
+	assert.True(small > 0.1 && small <= 0.5)
+
+	// overloaded network has shallow basins
+	overloaded, err := NewNetwork(size, "hebbian")
+	assert.NotNil(overloaded)
+	assert.NoError(err)
+	patterns = randomPatterns(15, size, rng)
+	err = overloaded.Store(patterns)
+	assert.NoError(err)
+	radius, err = overloaded.MeanBasinRadius(patterns, 5, 10, rng)
+	assert.NoError(err)
+	assert.True(radius < small)
+}
+
+func TestAttractorCount(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 51
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	err = n.Store(randomPatterns(1, size, rng))
+	assert.NoError(err)
+
+	errString := "invalid number of trials: %d"
+	count, err := n.AttractorCount(0, 10, rng)
+	assert.Equal(0, count)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	errString = "invalid number of iterations: %d"
+	count, err = n.AttractorCount(10, 0, rng)
+	assert.Equal(0, count)
+	assert.EqualError(err, fmt.Sprintf(errString, 0))
+
+	// the pattern and its inverse
+	count, err = n.AttractorCount(50, 10, rng)
+	assert.NoError(err)
+	assert.Equal(2, count)
+
+	// overloaded network has many spurious attractors
+	err = n.Store(randomPatterns(20, size, rng))
+	assert.NoError(err)
+	count, err = n.AttractorCount(50, 10, rng)
+	assert.NoError(err)
+	assert.True(count > 2)
+}
+
+func TestMutualInformation(t *testing.T) {
+	assert := assert.New(t)
+
+	var stored []*Pattern
+	errString := "invalid patterns supplied: %v"
+	mi, err := MutualInformation(stored, nil)
+	assert.Equal(0.0, mi)
+	assert.EqualError(err, fmt.Sprintf(errString, stored))
+
+	rng := rand.New(rand.NewSource(1))
+	stored = randomPatterns(10, 200, rng)
+	errString = "invalid number of recalled patterns: %d"
+	mi, err = MutualInformation(stored, stored[:5])
+	assert.Equal(0.0, mi)
+	assert.EqualError(err, fmt.Sprintf(errString, 5))
+
+	recalled := make([]*Pattern, len(stored))
+	copy(recalled, stored)
+	recalled[3] = nil
+	errString = "invalid pattern supplied: %d"
+	mi, err = MutualInformation(stored, recalled)
+	assert.Equal(0.0, mi)
+	assert.EqualError(err, fmt.Sprintf(errString, 3))
+
+	recalled[3] = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %d"
+	mi, err = MutualInformation(stored, recalled)
+	assert.Equal(0.0, mi)
+	assert.EqualError(err, fmt.Sprintf(errString, 2))
+
+	// perfect recall carries all the information of the nearly balanced stored bits
+	mi, err = MutualInformation(stored, stored)
+	assert.NoError(err)
+	assert.InDelta(1.0, mi, 0.01)
+
+	// inverted recall carries the same information
+	inverted := make([]*Pattern, len(stored))
+	for i, p := range stored {
+		inverted[i] = Invert(p.clone())
+	}
+	miInverted, err := MutualInformation(stored, inverted)
+	assert.NoError(err)
+	assert.InDelta(mi, miInverted, 1e-9)
+
+	// random recall carries almost no information
+	mi, err = MutualInformation(stored, randomPatterns(10, 200, rng))
+	assert.NoError(err)
+	assert.InDelta(0.0, mi, 0.01)
+}
+
+func TestBasinMontage(t *testing.T) {
+	assert := assert.New(t)
+
+	width, height := 4, 5
+	size := width * height
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	patterns := randomPatterns(3, size, rand.New(rand.NewSource(1)))
+	err = n.Store(patterns)
+	assert.NoError(err)
+	r := image.Rect(0, 0, width, height)
+
+	var invalid []*Pattern
+	errString := "invalid patterns supplied: %v"
+	img, err := BasinMontage(n, invalid, r, 10, 5)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, invalid))
+
+	errString = "invalid image dimensions: %dx%d"
+	img, err = BasinMontage(n, patterns, image.Rect(0, 0, width, width), 10, 5)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, width, width))
+
+	pcnt := 101
+	errString = "invalid noise percentage: %d"
+	img, err = BasinMontage(n, patterns, r, pcnt, 5)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, pcnt))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	img, err = BasinMontage(n, patterns, r, 10, iters)
+	assert.Nil(img)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	data := append([]float64(nil), patterns[0].RawData()...)
+	img, err = BasinMontage(n, patterns, r, 10, 5)
+	assert.NoError(err)
+	assert.Equal(image.Rect(0, 0, 3*width, len(patterns)*height), img.Bounds())
+	// original patterns are rendered in the first column
+	for i, p := range patterns {
+		tile := Pattern2Image(p, r)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				assert.Equal(tile.At(x, y), img.At(x, i*height+y))
+			}
+		}
+	}
+	assert.Equal(data, patterns[0].RawData())
+}
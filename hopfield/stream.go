@@ -0,0 +1,56 @@
+package hopfield
+
+import (
+	"context"
+	"sync"
+)
+
+// RestoreStream restores patterns received from in channel in async mode for iters iterations and sends
+// the restored patterns to the returned channel as they complete. Restores run in parallel in workers goroutines,
+// so the restored patterns may be sent in a different order than they were received. Each pattern is restored
+// on a copy, so the received patterns are not modified. Patterns which are nil or do not have the same dimension
+// as number of network neurons are dropped. Non-positive workers or iters are treated as 1.
+// The returned channel is closed once in is closed and all the received patterns are restored or when ctx is cancelled.
+func (n *Network) RestoreStream(ctx context.Context, in <-chan *Pattern, workers, iters int) <-chan *Pattern {
+	if workers <= 0 {
+		workers = 1
+	}
+	if iters <= 0 {
+		iters = 1
+	}
+	_, nCount := n.weights.Dims()
+	out := make(chan *Pattern)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case p, ok := <-in:
+					if !ok {
+						return
+					}
+					// drop invalid patterns
+					if p == nil || p.Len() != nCount {
+						continue
+					}
+					res, _ := n.restoreAsync(p.clone(), iters, nil)
+					select {
+					case out <- res:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
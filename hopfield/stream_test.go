@@ -0,0 +1,60 @@
+package hopfield
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreStream(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	in := make(chan *Pattern)
+	inputs := make([]*Pattern, len(patterns))
+	go func() {
+		defer close(in)
+		for i, p := range patterns {
+			inputs[i] = n.addNoise(p.clone(), 5, rng)
+			in <- inputs[i]
+		}
+		// invalid patterns are dropped
+		in <- nil
+		in <- Encode([]float64{1.0, -1.0})
+	}()
+
+	restored := make(map[uint64]bool)
+	count := 0
+	for res := range n.RestoreStream(context.Background(), in, 3, 10) {
+		restored[res.Hash()] = true
+		count++
+	}
+	assert.Equal(len(patterns), count)
+	for _, p := range patterns {
+		assert.True(restored[p.Hash()])
+	}
+	// inputs are restored on copies
+	for i, p := range inputs {
+		assert.NotEqual(patterns[i].RawData(), p.RawData())
+	}
+
+	// cancelled context closes the output channel
+	ctx, cancel := context.WithCancel(context.Background())
+	in = make(chan *Pattern)
+	out := n.RestoreStream(ctx, in, 2, 10)
+	cancel()
+	for range out {
+	}
+	close(in)
+}
@@ -0,0 +1,145 @@
+package hopfield
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// UpdateRule is the rule which drives network neuron updates during restore
+type UpdateRule int
+
+const (
+	// Synchronous updates all network neurons at once
+	Synchronous UpdateRule = iota
+	// AsyncRandom updates network neurons one by one in a pseudorandom order
+	AsyncRandom
+	// AsyncSequential updates network neurons one by one in the order of their indices
+	AsyncSequential
+	// Greedy updates network neurons one by one always picking the unstable neuron with the strongest local field
+	Greedy
+)
+
+// String returns the name of the update rule
+func (r UpdateRule) String() string {
+	switch r {
+	case Synchronous:
+		return "synchronous"
+	case AsyncRandom:
+		return "async random"
+	case AsyncSequential:
+		return "async sequential"
+	case Greedy:
+		return "greedy"
+	}
+
+	return fmt.Sprintf("UpdateRule(%d)", int(r))
+}
+
+// RestoreRule tries to restore supplied pattern from network using the update rule and returns it.
+// Network runs for iters iterations. Every iteration of Synchronous rule updates all the neurons at once,
+// every iteration of the asynchronous rules updates as many neurons as there are in the network.
+// Except for AsyncRandom, the restore stops early once the network converges.
+// It returns error if invalid pattern is supplied, iters is non-positive or unsupported update rule is supplied.
+// If the pattern is empty, ErrEmptyPattern is returned. If the network has not memorised any patterns,
+// error wrapping ErrUntrained is returned.
+func (n *Network) RestoreRule(p *Pattern, rule UpdateRule, iters int) (*Pattern, error) {
+	// pattern can't be nil
+	if p == nil {
+		return nil, fmt.Errorf("invalid pattern supplied: %v", p)
+	}
+	// pattern can't be empty
+	if p.Len() == 0 {
+		return nil, ErrEmptyPattern
+	}
+	// pattern length must be the same as number of neurons
+	_, nCount := n.weights.Dims()
+	if p.Len() != nCount {
+		return nil, fmt.Errorf("invalid pattern dimension: %v", p.Len())
+	}
+	// number of max iterations must be a positive integer
+	if iters <= 0 {
+		return nil, fmt.Errorf("invalid number of iterations: %d", iters)
+	}
+	if !n.Trained() {
+		return nil, n.errUntrained()
+	}
+
+	return n.restoreRule(p, rule, iters, n.rng)
+}
+
+// restoreRule restores supplied pattern from network using the update rule and returns it.
+// AsyncRandom rule uses rng to generate the order of neuron updates. If rng is nil, default source is used.
+// If the network starts restores from random states, the initial state is generated by rng, too.
+func (n *Network) restoreRule(p *Pattern, rule UpdateRule, iters int, rng *rand.Rand) (*Pattern, error) {
+	if n.randomStart {
+		copy(p.RawData(), n.randomState(rng).RawData())
+	}
+	var step func(*Pattern) bool
+	switch rule {
+	case Synchronous:
+		step = func(p *Pattern) bool { return n.syncStep(p, nil) }
+	case AsyncRandom:
+		return n.restoreAsync(p, iters, rng)
+	case AsyncSequential:
+		step = n.sequentialSweep
+	case Greedy:
+		step = n.greedySweep
+	default:
+		return nil, fmt.Errorf("unsupported update rule: %s", rule)
+	}
+	for iters > 0 && step(p) {
+		iters--
+	}
+
+	return p, nil
+}
+
+// sequentialSweep updates all network neurons one by one in the order of their indices
+// and reports whether any neuron changed its state
+func (n *Network) sequentialSweep(p *Pattern) bool {
+	changed := false
+	for i := 0; i < p.Len(); i++ {
+		nState := n.activation(i, n.localField(i, p), p.At(i))
+		if p.At(i) != nState {
+			p.RawData()[i] = nState
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// greedySweep performs as many updates as there are network neurons, each time updating the unstable neuron
+// whose local field is the furthest from its bias, and reports whether any neuron changed its state.
+// The sweep stops early if all the neurons are stable.
+func (n *Network) greedySweep(p *Pattern) bool {
+	// h stores local fields of all neurons
+	h := n.localFields(p)
+	changed := false
+	for k := 0; k < p.Len(); k++ {
+		best, bestField := -1, -1.0
+		for i := 0; i < p.Len(); i++ {
+			if n.activation(i, h.AtVec(i), p.At(i)) == p.At(i) {
+				continue
+			}
+			if field := math.Abs(h.AtVec(i) - n.bias.AtVec(i)); field > bestField {
+				best, bestField = i, field
+			}
+		}
+		// all neurons are stable
+		if best < 0 {
+			return changed
+		}
+		nState := n.activation(best, h.AtVec(best), p.At(best))
+		delta := nState - p.At(best)
+		p.RawData()[best] = nState
+		// update local fields of all neurons connected to the updated one
+		for j := 0; j < p.Len(); j++ {
+			h.SetVec(j, h.AtVec(j)+n.weights.At(j, best)*delta)
+		}
+		changed = true
+	}
+
+	return changed
+}
@@ -0,0 +1,118 @@
+package hopfield
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateRuleString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("synchronous", Synchronous.String())
+	assert.Equal("async random", AsyncRandom.String())
+	assert.Equal("async sequential", AsyncSequential.String())
+	assert.Equal("greedy", Greedy.String())
+	assert.Equal("UpdateRule(10)", UpdateRule(10).String())
+}
+
+func TestRestoreRule(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 100
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(5, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	var pattern *Pattern
+	errString := "invalid pattern supplied: %v"
+	res, err := n.RestoreRule(pattern, AsyncRandom, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern))
+
+	pattern = Encode([]float64{1.0, -1.0})
+	errString = "invalid pattern dimension: %v"
+	res, err = n.RestoreRule(pattern, AsyncRandom, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, pattern.Len()))
+
+	iters := 0
+	errString = "invalid number of iterations: %d"
+	res, err = n.RestoreRule(patterns[0].clone(), Synchronous, iters)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, iters))
+
+	rule := UpdateRule(10)
+	errString = "unsupported update rule: %s"
+	res, err = n.RestoreRule(patterns[0].clone(), rule, 10)
+	assert.Nil(res)
+	assert.EqualError(err, fmt.Sprintf(errString, rule))
+
+	for _, rule := range []UpdateRule{Synchronous, AsyncRandom, AsyncSequential, Greedy} {
+		for _, p := range patterns {
+			res, err := n.RestoreRule(n.addNoise(p.clone(), 5, rng), rule, 10)
+			assert.NoError(err, rule.String())
+			assert.Equal(p.RawData(), res.RawData(), rule.String())
+		}
+	}
+}
+
+func TestRestoreRuleGreedy(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(3, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	// every greedy update lowers the network energy
+	input := n.addNoise(patterns[0].clone(), 20, rng)
+	energy := n.energy(input)
+	for n.greedySweep(input) {
+		newEnergy := n.energy(input)
+		assert.True(newEnergy < energy)
+		energy = newEnergy
+	}
+	stable, err := n.Stable(input)
+	assert.NoError(err)
+	assert.True(stable)
+}
+
+func TestRestoreRuleRandomStart(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 50
+	n, err := NewNetwork(size, "hebbian", WithRandomStart(), WithSeed(1))
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.True(n.randomStart)
+
+	rng := rand.New(rand.NewSource(1))
+	patterns := randomPatterns(3, size, rng)
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	// restores from random states converge to fixed points regardless of the input
+	distinct := make(map[uint64]bool)
+	for i := 0; i < 20; i++ {
+		res, err := n.RestoreRule(patterns[0].clone(), AsyncSequential, 100)
+		assert.NoError(err)
+		stable, err := n.Stable(res)
+		assert.NoError(err)
+		assert.True(stable)
+		distinct[res.Hash()] = true
+	}
+	assert.True(len(distinct) > 1)
+}
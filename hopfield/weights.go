@@ -0,0 +1,168 @@
+package hopfield
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ConditionNumber computes 2-norm condition number of network weights matrix using SVD and returns it.
+// Large condition number indicates the stored patterns are nearly linearly dependent.
+// Condition number of singular weights matrix is +Inf.
+// It returns error if the SVD factorization of the weights matrix fails.
+func (n *Network) ConditionNumber() (float64, error) {
+	var svd mat.SVD
+	if ok := svd.Factorize(n.weights, mat.SVDNone); !ok {
+		return 0.0, fmt.Errorf("failed to factorize weights matrix")
+	}
+	// singular values are sorted in descending order
+	vals := svd.Values(nil)
+	if vals[len(vals)-1] == 0.0 {
+		return math.Inf(1), nil
+	}
+
+	return vals[0] / vals[len(vals)-1], nil
+}
+
+// Eigenvalues computes eigenvalues of network weights matrix and returns them sorted in ascending order.
+// It returns error if the eigendecomposition of the weights matrix fails.
+func (n *Network) Eigenvalues() ([]float64, error) {
+	var eig mat.EigenSym
+	if ok := eig.Factorize(n.weights, false); !ok {
+		return nil, fmt.Errorf("failed to factorize weights matrix")
+	}
+	vals := eig.Values(nil)
+	sort.Float64s(vals)
+
+	return vals, nil
+}
+
+// Frustration computes frustration index of network weights matrix and returns it.
+// Frustration index is the fraction of triangles of connected neurons with odd number of negative weights.
+// Zero weights are considered to be missing connections. High frustration indicates a rugged energy landscape
+// with many spurious minima. Frustration enumerates all the triangles, so it's only suitable for small networks.
+func (n *Network) Frustration() float64 {
+	size := n.weights.Symmetric()
+	triangles, frustrated := 0, 0
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			wij := n.weights.At(i, j)
+			if wij == 0.0 {
+				continue
+			}
+			for k := j + 1; k < size; k++ {
+				wik, wjk := n.weights.At(i, k), n.weights.At(j, k)
+				if wik == 0.0 || wjk == 0.0 {
+					continue
+				}
+				triangles++
+				// odd number of negative weights makes the product negative
+				if wij*wik*wjk < 0.0 {
+					frustrated++
+				}
+			}
+		}
+	}
+	if triangles == 0 {
+		return 0.0
+	}
+
+	return float64(frustrated) / float64(triangles)
+}
+
+// Degrees computes degree of every network neuron in the graph of strong connections and returns them.
+// Connection is strong if the magnitude of its weight exceeds threshold, so zero threshold counts all non-zero weights.
+// Highly connected neurons tend to be the most influential in recall.
+func (n *Network) Degrees(threshold float64) []int {
+	size := n.weights.Symmetric()
+	degrees := make([]int, size)
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			if math.Abs(n.weights.At(i, j)) > threshold {
+				degrees[i]++
+				degrees[j]++
+			}
+		}
+	}
+
+	return degrees
+}
+
+// DeadNeurons returns indices of the network neurons whose weights are all zero in ascending order.
+// Local field of a dead neuron is given only by the external field, so the neuron never responds
+// to the states of the other neurons. Before any pattern is stored all the neurons are dead.
+func (n *Network) DeadNeurons() []int {
+	var dead []int
+	for i, d := range n.Degrees(0.0) {
+		if d == 0 {
+			dead = append(dead, i)
+		}
+	}
+
+	return dead
+}
+
+// AsymmetryError returns the largest difference |Wᵢⱼ - Wⱼᵢ| over all pairs of network neurons.
+// Symmetric weights are required for the network energy to decrease during async restore.
+// Weights are stored in a symmetric matrix, so the error is zero unless the weights storage is broken.
+func (n *Network) AsymmetryError() float64 {
+	w := n.Weights()
+	size, _ := w.Dims()
+	max := 0.0
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			max = math.Max(max, math.Abs(w.At(i, j)-w.At(j, i)))
+		}
+	}
+
+	return max
+}
+
+// ClipWeights clamps all network weights to [-max, max]. Clipping keeps the weights matrix symmetric
+// and its diagonal zero. It bounds the weights which grow large as more patterns are stored and dominate
+// recall of the weaker memories. If max is negative, its absolute value is used.
+func (n *Network) ClipWeights(max float64) {
+	max = math.Abs(max)
+	size := n.weights.Symmetric()
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			n.weights.SetSym(i, j, math.Max(-max, math.Min(max, n.weights.At(i, j))))
+		}
+	}
+}
+
+// WeightsHeatmap renders network weights matrix as an NxN heatmap image and returns it, where N is the network size.
+// Pixel at (x, y) represents the weight of the connection between neurons y and x. Positive weights are red,
+// negative weights are blue and the color intensity is proportional to the weight magnitude relative to the largest one.
+// Zero weights are black.
+func (n *Network) WeightsHeatmap() image.Image {
+	size := n.weights.Symmetric()
+	// largest weight magnitude scales the color intensity
+	max := 0.0
+	for i := 0; i < size; i++ {
+		for j := i; j < size; j++ {
+			max = math.Max(max, math.Abs(n.weights.At(i, j)))
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := color.RGBA{A: 255}
+			if w := n.weights.At(y, x); max > 0.0 {
+				intensity := uint8(math.Round(255 * math.Abs(w) / max))
+				if w > 0.0 {
+					c.R = intensity
+				} else {
+					c.B = intensity
+				}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
@@ -0,0 +1,235 @@
+package hopfield
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionNumber(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 8
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	// untrained network has singular weights matrix
+	cond, err := n.ConditionNumber()
+	assert.NoError(err)
+	assert.True(math.IsInf(cond, 1))
+
+	// orthogonal patterns
+	err = n.Store([]*Pattern{
+		Encode([]float64{1, 1, 1, 1, -1, -1, -1, -1}),
+		Encode([]float64{1, 1, -1, -1, 1, 1, -1, -1}),
+	})
+	assert.NoError(err)
+	condSep, err := n.ConditionNumber()
+	assert.NoError(err)
+	assert.InDelta(3.0, condSep, 0.0001)
+
+	// patterns which differ in a single neuron
+	n, err = NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	err = n.Store([]*Pattern{
+		Encode([]float64{1, 1, 1, 1, -1, -1, -1, -1}),
+		Encode([]float64{1, 1, 1, 1, -1, -1, -1, 1}),
+	})
+	assert.NoError(err)
+	condDup, err := n.ConditionNumber()
+	assert.NoError(err)
+	assert.True(condDup > 1000*condSep)
+}
+
+func TestEigenvalues(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 8
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	err = n.Store([]*Pattern{Encode([]float64{1, 1, -1, 1, -1, -1, -1, 1})})
+	assert.NoError(err)
+
+	vals, err := n.Eigenvalues()
+	assert.NoError(err)
+	assert.Len(vals, size)
+	// the stored pattern is the eigenvector of the dominant eigenvalue (N-1)/N,
+	// the rest of the eigenvalues are -1/N
+	for i := 0; i < size-1; i++ {
+		assert.InDelta(-1.0/float64(size), vals[i], 1e-9)
+	}
+	assert.InDelta(float64(size-1)/float64(size), vals[size-1], 1e-9)
+}
+
+func TestFrustration(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	// untrained network has no triangles
+	assert.Equal(0.0, n.Frustration())
+
+	// ferromagnetic network
+	err = n.Store([]*Pattern{Encode([]float64{1.0, 1.0, 1.0, 1.0})})
+	assert.NoError(err)
+	assert.Equal(0.0, n.Frustration())
+
+	// one negative weight frustrates the two triangles it is part of
+	n.weights.SetSym(0, 1, -1.0)
+	assert.InDelta(0.5, n.Frustration(), 1e-9)
+
+	// triangle 0,1,2 now has two negative weights
+	n.weights.SetSym(0, 2, -1.0)
+	assert.InDelta(0.5, n.Frustration(), 1e-9)
+
+	// removing a weight removes its triangles
+	n.weights.SetSym(2, 3, 0.0)
+	assert.InDelta(1.0/2.0, n.Frustration(), 1e-9)
+}
+
+func TestDegrees(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 4
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.Equal([]int{0, 0, 0, 0}, n.Degrees(0.0))
+
+	// star with strong center 0 and weak edge between 2 and 3
+	n.weights.SetSym(0, 1, 0.5)
+	n.weights.SetSym(0, 2, -0.5)
+	n.weights.SetSym(0, 3, 0.4)
+	n.weights.SetSym(2, 3, 0.1)
+
+	assert.Equal([]int{3, 1, 2, 2}, n.Degrees(0.0))
+	assert.Equal([]int{3, 1, 1, 1}, n.Degrees(0.2))
+	assert.Equal([]int{2, 1, 1, 0}, n.Degrees(0.4))
+	assert.Equal([]int{0, 0, 0, 0}, n.Degrees(0.5))
+}
+
+func TestDeadNeurons(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 10
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+	assert.Len(n.DeadNeurons(), size)
+
+	err = n.Store(randomPatterns(3, size, rand.New(rand.NewSource(1))))
+	assert.NoError(err)
+	assert.Nil(n.DeadNeurons())
+
+	// zero the rows of neurons 2 and 7
+	for _, i := range []int{2, 7} {
+		for j := 0; j < size; j++ {
+			if j != i {
+				n.weights.SetSym(i, j, 0.0)
+			}
+		}
+	}
+	assert.Equal([]int{2, 7}, n.DeadNeurons())
+}
+
+func TestAsymmetryError(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 20
+	for _, method := range []string{"hebbian", "storkey"} {
+		n, err := NewNetwork(size, method)
+		assert.NotNil(n)
+		assert.NoError(err)
+		assert.Equal(0.0, n.AsymmetryError())
+
+		err = n.Store(randomPatterns(5, size, rand.New(rand.NewSource(1))))
+		assert.NoError(err)
+		assert.Equal(0.0, n.AsymmetryError())
+	}
+}
+
+func TestClipWeights(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 20
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	// correlated patterns grow large weights
+	patterns := randomPatterns(10, size, rand.New(rand.NewSource(1)))
+	for i := 1; i < len(patterns); i++ {
+		copy(patterns[i].RawData()[:size/2], patterns[0].RawData()[:size/2])
+	}
+	err = n.Store(patterns)
+	assert.NoError(err)
+
+	max := 0.1
+	exceeded := false
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			exceeded = exceeded || math.Abs(n.weights.At(i, j)) > max
+		}
+	}
+	assert.True(exceeded)
+
+	n.ClipWeights(max)
+	for i := 0; i < size; i++ {
+		assert.Equal(0.0, n.weights.At(i, i))
+		for j := 0; j < size; j++ {
+			assert.True(math.Abs(n.weights.At(i, j)) <= max)
+			assert.Equal(n.weights.At(i, j), n.weights.At(j, i))
+		}
+	}
+
+	// negative bound clips the same way
+	n.ClipWeights(-max / 2)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			assert.True(math.Abs(n.weights.At(i, j)) <= max/2)
+		}
+	}
+}
+
+func TestWeightsHeatmap(t *testing.T) {
+	assert := assert.New(t)
+
+	size := 5
+	n, err := NewNetwork(size, "hebbian")
+	assert.NotNil(n)
+	assert.NoError(err)
+
+	// untrained network renders black image
+	img := n.WeightsHeatmap()
+	assert.Equal(image.Rect(0, 0, size, size), img.Bounds())
+	assert.Equal(color.RGBA{A: 255}, img.At(size/2, size/2))
+
+	err = n.Store([]*Pattern{Encode([]float64{1, -1, 1, -1, 1})})
+	assert.NoError(err)
+
+	img = n.WeightsHeatmap()
+	assert.Equal(image.Rect(0, 0, size, size), img.Bounds())
+	// neurons 1 and 2 are anti-correlated, neurons 2 and 4 are correlated
+	assert.True(n.weights.At(size/2, size/2-1) < 0.0)
+	r, g, b, _ := img.At(size/2-1, size/2).RGBA()
+	assert.Equal(uint32(0), r)
+	assert.Equal(uint32(0), g)
+	assert.True(b > 0)
+	assert.True(n.weights.At(size/2, size-1) > 0.0)
+	r, g, b, _ = img.At(size-1, size/2).RGBA()
+	assert.True(r > 0)
+	assert.Equal(uint32(0), g)
+	assert.Equal(uint32(0), b)
+	// zero diagonal renders black
+	assert.Equal(color.RGBA{A: 255}, img.At(size/2, size/2))
+}